@@ -9,12 +9,19 @@ import (
 	color "github.com/TwiN/go-color"
 	logger "github.com/jsandas/gologger"
 	"github.com/jsandas/tlstools/pkg/scanner"
+	"github.com/jsandas/tlstools/pkg/ssl"
 	"github.com/jsandas/tlstools/pkg/utils"
 )
 
 func main() {
 	scanHost := flag.String("host", "", "hostname/ip address to scan")
 	scanPort := flag.String("port", "443", "port to scan (default: 443")
+	concurrency := flag.Int("concurrency", scanner.DefaultOptions().Concurrency, fmt.Sprintf("maximum concurrent connections against the host (1-%d)", ssl.MaxConcurrency))
+	mode := flag.String("mode", scanner.DefaultOptions().Mode, "cipher enumeration mode (elimination or exhaustive)")
+	drownPorts := flag.String("drown-ports", "", "comma separated ports of the host to check for sslv2 with the same rsa key")
+	checks := flag.String("checks", "", "comma separated vulnerability checks to run (default: all)")
+	disabledChecks := flag.String("disable-checks", "", "comma separated vulnerability checks to skip")
+	xmppDomain := flag.String("xmpp-domain", "", "domain sent in the xmpp stream header (default: host)")
 	flag.Parse()
 
 	logger.LogLevel = "CRITICAL"
@@ -24,28 +31,52 @@ func main() {
 		return
 	}
 
+	if *concurrency < 1 || *concurrency > ssl.MaxConcurrency {
+		fmt.Printf(" invalid concurrency provided: %d (1-%d)", *concurrency, ssl.MaxConcurrency)
+		return
+	}
+
 	if !utils.CanConnect(*scanHost, *scanPort) {
 		fmt.Printf(" host unreachable: %s:%s", *scanHost, *scanPort)
 		return
 	}
 
-	scanCert(*scanHost, *scanPort)
+	opts := scanner.DefaultOptions()
+	opts.Concurrency = *concurrency
+	opts.Mode = *mode
+	opts.XMPPDomain = *xmppDomain
+	if *drownPorts != "" {
+		opts.DROWNPorts = strings.Split(*drownPorts, ",")
+	}
+
+	if *checks != "" {
+		opts.Checks = strings.Split(*checks, ",")
+	}
+	if *disabledChecks != "" {
+		opts.DisabledChecks = strings.Split(*disabledChecks, ",")
+	}
+	if !scanner.DefaultRegistry.Valid(opts.Checks) || !scanner.DefaultRegistry.Valid(opts.DisabledChecks) {
+		fmt.Printf(" invalid checks provided, available: %s", strings.Join(scanner.DefaultRegistry.IDs(), ","))
+		return
+	}
+
+	scanCert(*scanHost, *scanPort, opts)
 
-	scanConfig(*scanHost, *scanPort)
+	scanConfig(*scanHost, *scanPort, opts)
 }
 
-func scanCert(host string, port string) {
+func scanCert(host string, port string, opts scanner.Options) {
 	var results scanner.CertificateData
 
-	results.ScanCertificate(host, port)
+	results.ScanCertificateWithOptions(host, port, opts)
 
 	printCertResults(results)
 }
 
-func scanConfig(host string, port string) {
+func scanConfig(host string, port string, opts scanner.Options) {
 	var results scanner.ConfigurationData
 
-	results.ScanConfiguration(host, port)
+	results.ScanConfigurationWithOptions(host, port, opts)
 
 	printConfigResults(results)
 }
@@ -86,11 +117,72 @@ func printCertResults(results scanner.CertificateData) {
 func printConfigResults(results scanner.ConfigurationData) {
 	fmt.Print(color.Ize(color.Green, "OCSP Stapling:"))
 	fmt.Println(color.Ize(color.Cyan, " "+fmt.Sprintf("%v", results.OCSPStapling)))
+	fmt.Println(color.Ize(color.Green, "Features:"))
+	printFeature("Secure Renegotiation", results.Features.SecureRenegotiation)
+	printFeature("Extended Master Secret", results.Features.ExtendedMasterSecret)
+	printFeature("Encrypt-then-MAC", results.Features.EncryptThenMAC)
+	printFeature("Session Tickets", fmt.Sprintf("%v (lifetime hint %ds)", results.Features.SessionTickets, results.Features.SessionTicketLifetime))
+	printFeature("Session ID Resumption", results.Features.SessionIDResumption)
+	printFeature("ALPN", strings.Join(results.Features.ALPN, " "))
+	printFeature("NPN", strings.Join(results.Features.NPN, " "))
+	printFeature("OCSP Must-Staple", results.Features.OCSPMustStaple)
+	printFeature("SCT Extension", results.Features.SCTExtension)
+	printFeature("Heartbeat", results.Features.Heartbeat)
+	if results.Features.XMPPTLSRequired != nil {
+		printFeature("XMPP TLS Required", *results.Features.XMPPTLSRequired)
+	}
+	if results.Features.RDPSecurity != "" {
+		printFeature("RDP Security", results.Features.RDPSecurity)
+	}
+	fmt.Print(color.Ize(color.Green, "Intolerant ClientHellos:"))
+	fmt.Println(color.Ize(color.Cyan, " "+strings.Join(results.Intolerance.Intolerant, " ")))
 	fmt.Print(color.Ize(color.Green, "Server Header:"))
 	fmt.Println(color.Ize(color.Cyan, " "+results.ServerHeader))
 	fmt.Println(color.Ize(color.Green, "Protocols/Ciphers:"))
 	for proto, ciphers := range results.SupportedConfig {
+		fmt.Println(color.Ize(color.Green, "   "+proto+":"))
+		for _, c := range ciphers {
+			info := fmt.Sprintf(" %s %d bits", c.CodePoint, c.Bits)
+			if c.ForwardSecrecy {
+				info += " FS"
+			}
+			if c.AEAD {
+				info += " AEAD"
+			}
+			if c.KeyExchangeParams != nil {
+				info += fmt.Sprintf(" (%s %s %d)", c.KeyExchangeParams.Type, c.KeyExchangeParams.Group, c.KeyExchangeParams.Size)
+			}
+			fmt.Println(color.Ize(color.Cyan, "      "+c.Name+info))
+		}
+	}
+	fmt.Println(color.Ize(color.Green, "Key Exchange Groups:"))
+	for proto, groups := range results.SupportedGroups {
 		fmt.Print(color.Ize(color.Green, "   "+proto+":"))
-		fmt.Println(color.Ize(color.Cyan, " "+strings.Join(ciphers, " ")))
+		fmt.Println(color.Ize(color.Cyan, " "+strings.Join(groups, " ")))
+	}
+	fmt.Println(color.Ize(color.Green, "Signature Algorithms:"))
+	for proto, schemes := range results.SignatureAlgorithms {
+		fmt.Println(color.Ize(color.Green, "   "+proto+":"))
+		for _, s := range schemes {
+			c := color.Cyan
+			if s.Weak {
+				c = color.Red
+			}
+			fmt.Println(color.Ize(c, "      "+s.Name))
+		}
 	}
+	fmt.Println(color.Ize(color.Green, "Cipher Preference:"))
+	for proto, pref := range results.CipherPreference {
+		order := pref.Order
+		if pref.Incomplete {
+			order += " (incomplete)"
+		}
+		fmt.Print(color.Ize(color.Green, "   "+proto+":"))
+		fmt.Println(color.Ize(color.Cyan, " "+order+" "+strings.Join(pref.Ciphers, " ")))
+	}
+}
+
+func printFeature(name string, value interface{}) {
+	fmt.Print(color.Ize(color.Green, "   "+name+":"))
+	fmt.Println(color.Ize(color.Cyan, " "+fmt.Sprintf("%v", value)))
 }
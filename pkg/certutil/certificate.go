@@ -19,6 +19,7 @@ type CertData struct {
 	Fingerprints       map[string]string `json:"fingerprints"`
 	Issuer             Issuer            `json:"issuer"`
 	KeyType            string            `json:"keyType"`
+	KeyWeaknesses      []KeyWeakness     `json:"keyWeaknesses"`
 	SerialNumber       string            `json:"serialNumber"`
 	SignatureAlgorithm string            `json:"signatureAlgorithm"`
 	Status             Status            `json:"status"`
@@ -98,6 +99,7 @@ func (c *CertData) Process(cert *x509.Certificate) {
 	h = sha256.New()
 	h.Write(cert.Raw)
 	f["sha256"] = hex.EncodeToString(h.Sum(nil))
+	f["publicKeySha256"] = PublicKeyFingerprint(cert)
 	c.Fingerprints = f
 
 	// issuer data
@@ -117,12 +119,20 @@ func (c *CertData) Process(cert *x509.Certificate) {
 
 	// other cert data
 	c.KeyType = getKeyType(cert.PublicKey, cert.PublicKeyAlgorithm)
+	c.KeyWeaknesses = KeyWeaknesses(cert.PublicKey)
 	c.SerialNumber = getSerialString(cert.SerialNumber)
 	c.SignatureAlgorithm = cert.SignatureAlgorithm.String()
 	c.ValidFrom = cert.NotBefore
 	c.ValidTo = cert.NotAfter
 }
 
+// PublicKeyFingerprint returns the sha256 hash of the certificate's
+// SubjectPublicKeyInfo, which is shared by certificates for the same key
+func PublicKeyFingerprint(cert *x509.Certificate) string {
+	h := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return hex.EncodeToString(h[:])
+}
+
 func getSerialString(s *big.Int) string {
 
 	serial := strings.ToUpper(s.Text(16))
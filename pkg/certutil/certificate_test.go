@@ -117,6 +117,10 @@ func TestParseRSACert(t *testing.T) {
 	if c.SerialNumber != "01" {
 		t.Errorf("Certificate incorrect serial number, got: %s, want: %s.", c.SerialNumber, "01")
 	}
+
+	if f := c.Fingerprints["publicKeySha256"]; len(f) != 64 || f != PublicKeyFingerprint(cert) {
+		t.Errorf("Certificate incorrect public key fingerprint, got: %s", f)
+	}
 }
 
 func TestParseECDSACert(t *testing.T) {
@@ -10,6 +10,7 @@ import (
 type CSRData struct {
 	Extensions         CSRExtensions `json:"extensions"`
 	KeyType            string        `json:"keyType"`
+	KeyWeaknesses      []KeyWeakness `json:"keyWeaknesses"`
 	SignatureAlgorithm string        `json:"signatureAlgorithm"`
 	Subject            Subject       `json:"subject"`
 	Version            int           `json:"version"`
@@ -20,8 +21,9 @@ type CSRExtensions struct {
 	SubjectAlternativeNames []string `json:"subjectAlternativeNames"`
 }
 
-// ParseCSR returns data from provided csr
-func (c *CSRData) Process(csr x509.CertificateRequest) {
+// ParseCSR returns data from provided csr. ErrWeakKey is returned when
+// the public key has weaknesses, the csr must not be signed then
+func (c *CSRData) Process(csr x509.CertificateRequest) error {
 	// extensions
 	c.Extensions.SubjectAlternativeNames = csr.DNSNames
 
@@ -38,5 +40,7 @@ func (c *CSRData) Process(csr x509.CertificateRequest) {
 	c.KeyType = getKeyType(csr.PublicKey, csr.PublicKeyAlgorithm)
 	c.SignatureAlgorithm = csr.SignatureAlgorithm.String()
 	c.Version = csr.Version
+	c.KeyWeaknesses = KeyWeaknesses(csr.PublicKey)
 
+	return weakKeyError(c.KeyWeaknesses)
 }
@@ -0,0 +1,144 @@
+package certutil
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// MinRSAKeySize is the smallest RSA modulus accepted by policy
+const MinRSAKeySize = 2048
+
+// fermatRounds is the number of Fermat factorization steps tried on a
+// modulus. Moduli of primes closer than about 2^(bits/4) are factored in
+// the first step
+const fermatRounds = 100
+
+// rsaExponent is the public exponent used by virtually all RSA keys
+const rsaExponent = 65537
+
+// key weakness ids
+const (
+	WeaknessROCA            = "roca"
+	WeaknessFermat          = "fermat"
+	WeaknessSmallExponent   = "smallExponent"
+	WeaknessUnusualExponent = "unusualExponent"
+	WeaknessSmallModulus    = "smallModulus"
+)
+
+// ErrWeakKey is returned when a CSR has a weak public key
+var ErrWeakKey = errors.New("weak public key")
+
+// KeyWeakness is a weakness of a public key
+type KeyWeakness struct {
+	ID     string `json:"id"`
+	Detail string `json:"detail"`
+}
+
+// rocaPrimes are the small primes used by the fingerprint of keys
+// generated by the Infineon library affected by ROCA (CVE-2017-15361)
+var rocaPrimes = []int64{3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37, 41, 43, 47, 53, 59, 61, 67, 71, 73, 79, 83, 89, 97, 101, 103, 107, 109, 113, 127, 131, 137, 139, 149, 151, 157, 163, 167}
+
+// rocaSubgroups are the powers of 65537 modulo each of rocaPrimes
+var rocaSubgroups = func() map[int64]map[int64]bool {
+	groups := make(map[int64]map[int64]bool)
+	for _, p := range rocaPrimes {
+		g := make(map[int64]bool)
+		for x := int64(1); !g[x]; x = x * rsaExponent % p {
+			g[x] = true
+		}
+		groups[p] = g
+	}
+	return groups
+}()
+
+// KeyWeaknesses runs the key weakness detectors on pub. Only RSA keys
+// are checked
+func KeyWeaknesses(pub interface{}) []KeyWeakness {
+	key, ok := pub.(*rsa.PublicKey)
+	if !ok {
+		return nil
+	}
+
+	var w []KeyWeakness
+
+	if size := key.N.BitLen(); size < MinRSAKeySize {
+		w = append(w, KeyWeakness{WeaknessSmallModulus, fmt.Sprintf("%d bit modulus is below %d bits", size, MinRSAKeySize)})
+	}
+
+	switch {
+	case key.E < rsaExponent:
+		w = append(w, KeyWeakness{WeaknessSmallExponent, fmt.Sprintf("public exponent %d", key.E)})
+	case key.E != rsaExponent:
+		w = append(w, KeyWeakness{WeaknessUnusualExponent, fmt.Sprintf("public exponent %d", key.E)})
+	}
+
+	if rocaFingerprint(key.N) {
+		w = append(w, KeyWeakness{WeaknessROCA, "modulus matches the Infineon fingerprint (CVE-2017-15361)"})
+	}
+
+	if p := fermatFactor(key.N); p != nil {
+		w = append(w, KeyWeakness{WeaknessFermat, fmt.Sprintf("modulus factored with Fermat's method (%d bit factor)", p.BitLen())})
+	}
+
+	return w
+}
+
+// rocaFingerprint reports if n modulo each of rocaPrimes is a power of
+// 65537, which holds for the primes generated by the Infineon library
+func rocaFingerprint(n *big.Int) bool {
+	r := new(big.Int)
+	for _, p := range rocaPrimes {
+		r.Mod(n, big.NewInt(p))
+		if !rocaSubgroups[p][r.Int64()] {
+			return false
+		}
+	}
+	return true
+}
+
+// fermatFactor tries to factor n as a^2 - b^2 starting at a = ceil(sqrt(n))
+// and returns the smaller factor if found
+func fermatFactor(n *big.Int) *big.Int {
+	if n.Sign() <= 0 || n.Bit(0) == 0 {
+		return nil
+	}
+
+	a := new(big.Int).Sqrt(n)
+	if new(big.Int).Mul(a, a).Cmp(n) < 0 {
+		a.Add(a, big.NewInt(1))
+	}
+
+	b2 := new(big.Int)
+	b := new(big.Int)
+	for i := 0; i < fermatRounds; i++ {
+		b2.Mul(a, a)
+		b2.Sub(b2, n)
+		b.Sqrt(b2)
+		if new(big.Int).Mul(b, b).Cmp(b2) == 0 {
+			p := new(big.Int).Sub(a, b)
+			if p.Cmp(big.NewInt(1)) > 0 {
+				return p
+			}
+			return nil
+		}
+		a.Add(a, big.NewInt(1))
+	}
+
+	return nil
+}
+
+// weakKeyError returns ErrWeakKey listing the weaknesses or nil
+func weakKeyError(w []KeyWeakness) error {
+	if len(w) == 0 {
+		return nil
+	}
+
+	var ids []string
+	for _, k := range w {
+		ids = append(ids, k.ID)
+	}
+	return fmt.Errorf("%w: %s", ErrWeakKey, strings.Join(ids, ", "))
+}
@@ -0,0 +1,139 @@
+package certutil
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func weaknessIDs(w []KeyWeakness) []string {
+	var ids []string
+	for _, k := range w {
+		ids = append(ids, k.ID)
+	}
+	return ids
+}
+
+// rocaPrime returns a 1024 bit number of the form k*M + 65537^a mod M
+// used for the primes of the Infineon library, M being the product of
+// rocaPrimes. The number is above 2^1023.5 so that the product of two is
+// a 2048 bit modulus
+func rocaPrime(t *testing.T) *big.Int {
+	m := big.NewInt(1)
+	for _, p := range rocaPrimes {
+		m.Mul(m, big.NewInt(p))
+	}
+
+	lo := new(big.Int).Sqrt(new(big.Int).Lsh(big.NewInt(1), 2047))
+	lo.Div(lo, m).Add(lo, big.NewInt(1))
+	hi := new(big.Int).Lsh(big.NewInt(1), 1024)
+	hi.Div(hi, m).Sub(hi, big.NewInt(1))
+
+	k, err := rand.Int(rand.Reader, new(big.Int).Sub(hi, lo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	k.Add(k, lo)
+
+	a, err := rand.Int(rand.Reader, big.NewInt(1<<20))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n := new(big.Int).Mul(k, m)
+	return n.Add(n, new(big.Int).Exp(big.NewInt(rsaExponent), a, m))
+}
+
+func TestKeyWeaknesses(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	small, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := rand.Prime(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := new(big.Int).Add(p, big.NewInt(2))
+	for !q.ProbablyPrime(20) {
+		q.Add(q, big.NewInt(2))
+	}
+
+	var tests = []struct {
+		name string
+		key  *rsa.PublicKey
+		want []string
+	}{
+		{"good", &key.PublicKey, nil},
+		{"small modulus", &small.PublicKey, []string{WeaknessSmallModulus}},
+		{"small exponent", &rsa.PublicKey{N: key.N, E: 3}, []string{WeaknessSmallExponent}},
+		{"unusual exponent", &rsa.PublicKey{N: key.N, E: 65539}, []string{WeaknessUnusualExponent}},
+		{"roca", &rsa.PublicKey{N: new(big.Int).Mul(rocaPrime(t), rocaPrime(t)), E: rsaExponent}, []string{WeaknessROCA}},
+		{"fermat", &rsa.PublicKey{N: new(big.Int).Mul(p, q), E: rsaExponent}, []string{WeaknessFermat}},
+	}
+
+	for _, test := range tests {
+		if got := weaknessIDs(KeyWeaknesses(test.key)); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestKeyWeaknessesECDSA(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if w := KeyWeaknesses(&key.PublicKey); w != nil {
+		t.Errorf("ecdsa key should not be checked, got: %v", w)
+	}
+}
+
+func TestFermatFactor(t *testing.T) {
+	// 101 * 103
+	if p := fermatFactor(big.NewInt(10403)); p == nil || p.Int64() != 101 {
+		t.Errorf("wrong factor, got: %v", p)
+	}
+
+	// 3 * 10007 is too far apart
+	if p := fermatFactor(big.NewInt(30021)); p != nil {
+		t.Errorf("should not be factored, got: %v", p)
+	}
+}
+
+func TestProcessCSRWeakKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{Subject: pkix.Name{CommonName: "weak.example.com"}}, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	csr, err := x509.ParseCertificateRequest(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var c CSRData
+	err = c.Process(*csr)
+	if !errors.Is(err, ErrWeakKey) {
+		t.Errorf("weak csr should be rejected, got: %v", err)
+	}
+	if !reflect.DeepEqual(weaknessIDs(c.KeyWeaknesses), []string{WeaknessSmallModulus}) {
+		t.Errorf("wrong weaknesses, got: %v", c.KeyWeaknesses)
+	}
+}
@@ -77,7 +77,13 @@ func csrHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	c.Process(*csr)
+	if err := c.Process(*csr); err != nil {
+		logger.Warnf("event_id=weak_csr_key cn=%s msg=\"%v\"", c.Subject.CommonName, err)
+		render.Status(r, http.StatusBadRequest)
+		m := map[string]string{"400": err.Error()}
+		render.JSON(w, r, m)
+		return
+	}
 
 	render.Status(r, http.StatusOK)
 	render.JSON(w, r, c)
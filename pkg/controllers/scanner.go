@@ -2,12 +2,14 @@ package controllers
 
 import (
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 	logger "github.com/jsandas/gologger"
 	"github.com/jsandas/tlstools/pkg/scanner"
+	"github.com/jsandas/tlstools/pkg/ssl"
 	"github.com/jsandas/tlstools/pkg/utils"
 )
 
@@ -86,7 +88,88 @@ func scanConfigHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	results.ScanConfiguration(scanHost, scanPort)
+	opts := scanner.DefaultOptions()
+	if c := r.URL.Query().Get("concurrency"); c != "" {
+		n, err := strconv.Atoi(c)
+		if err != nil || n < 1 || n > ssl.MaxConcurrency {
+			logger.Warnf("event_id=invalid_concurrency value=%s", c)
+			render.Status(r, http.StatusBadRequest)
+
+			m := map[string]string{"400": "invalid concurrency"}
+			render.JSON(w, r, m)
+			return
+		}
+		opts.Concurrency = n
+	}
+
+	if mode := r.URL.Query().Get("mode"); mode != "" {
+		if !ssl.ValidMode(mode) {
+			logger.Warnf("event_id=invalid_mode value=%s", mode)
+			render.Status(r, http.StatusBadRequest)
+
+			m := map[string]string{"400": "invalid mode"}
+			render.JSON(w, r, m)
+			return
+		}
+		opts.Mode = mode
+	}
+
+	if schema := r.URL.Query().Get("schema"); schema != "" {
+		n, err := strconv.Atoi(schema)
+		if err != nil || (n != scanner.SchemaLegacy && n != scanner.SchemaCurrent) {
+			logger.Warnf("event_id=invalid_schema value=%s", schema)
+			render.Status(r, http.StatusBadRequest)
+
+			m := map[string]string{"400": "invalid schema"}
+			render.JSON(w, r, m)
+			return
+		}
+		opts.SchemaVersion = n
+	}
+
+	if ports := r.URL.Query().Get("drownPorts"); ports != "" {
+		for _, p := range strings.Split(ports, ",") {
+			if !utils.ValidPort(p) {
+				logger.Warnf("event_id=invalid_drown_port value=%s", p)
+				render.Status(r, http.StatusBadRequest)
+
+				m := map[string]string{"400": "invalid drown ports"}
+				render.JSON(w, r, m)
+				return
+			}
+			opts.DROWNPorts = append(opts.DROWNPorts, p)
+		}
+	}
+
+	for param, ids := range map[string]*[]string{"checks": &opts.Checks, "disabledChecks": &opts.DisabledChecks} {
+		v := r.URL.Query().Get(param)
+		if v == "" {
+			continue
+		}
+		*ids = strings.Split(v, ",")
+		if !scanner.DefaultRegistry.Valid(*ids) {
+			logger.Warnf("event_id=invalid_checks param=%s value=%s", param, v)
+			render.Status(r, http.StatusBadRequest)
+
+			m := map[string]string{"400": "invalid checks"}
+			render.JSON(w, r, m)
+			return
+		}
+	}
+
+	if domain := r.URL.Query().Get("xmppDomain"); domain != "" {
+		if !utils.ValidHost(domain) {
+			logger.Warnf("event_id=invalid_xmpp_domain value=%s", domain)
+			render.Status(r, http.StatusBadRequest)
+
+			m := map[string]string{"400": "invalid xmpp domain"}
+			render.JSON(w, r, m)
+			return
+		}
+		opts.XMPPDomain = domain
+	}
+
+	results.ScanConfigurationContext(r.Context(), scanHost, scanPort, opts)
 
 	render.Status(r, http.StatusOK)
 	render.JSON(w, r, results)
@@ -0,0 +1,434 @@
+package scanner
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/tls"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	logger "github.com/jsandas/gologger"
+	"github.com/jsandas/tls-vuln-checker/vulnerabilities/ccs"
+	"github.com/jsandas/tls-vuln-checker/vulnerabilities/debianweakkey"
+	"github.com/jsandas/tls-vuln-checker/vulnerabilities/heartbleed"
+	"github.com/jsandas/tlstools/pkg/ssl"
+)
+
+// severities of a Finding
+const (
+	SeverityLow      = "low"
+	SeverityMedium   = "medium"
+	SeverityHigh     = "high"
+	SeverityCritical = "critical"
+)
+
+// statuses of a Finding not reported by the checks themselves
+const (
+	statusVulnerable    = "yes"
+	statusNotVulnerable = "no"
+	statusNotApplicable = "n/a"
+	statusFailed        = "error"
+)
+
+// Finding is the result of a VulnerabilityCheck
+type Finding struct {
+	ID       string `json:"id"`
+	Severity string `json:"severity"`
+	// Status is the verdict of the check (yes, no, n/a or error)
+	Status   string   `json:"status"`
+	Evidence []string `json:"evidence,omitempty"`
+	// Details is the result of the ssl package check, if any
+	Details interface{} `json:"details,omitempty"`
+}
+
+// Target is the scanned server shared by all checks of a scan
+type Target struct {
+	Host string
+	Port string
+	// ConnState is the state of the handshake made by the scanner
+	ConnState tls.ConnectionState
+	// TLSVersion is the protocol negotiated in that handshake
+	TLSVersion int
+	// Options are passed to the ssl package, checks dialing on their own
+	// hold one of its connections with Options.Acquire
+	Options ssl.Options
+	// SupportedConfig are the names of the supported ciphers by protocol,
+	// checks read them with Supported
+	SupportedConfig map[string][]string
+
+	// supportedReady is closed once the scan has set SupportedConfig, the
+	// ciphers are enumerated while the checks run
+	supportedReady chan struct{}
+}
+
+// Supported waits until the supported ciphers are enumerated and returns
+// them. Checks must not hold a connection of Options while waiting
+func (t *Target) Supported(ctx context.Context) (map[string][]string, error) {
+	if t.supportedReady != nil {
+		select {
+		case <-t.supportedReady:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return t.SupportedConfig, nil
+}
+
+// VulnerabilityCheck is a check run against a Target. Run must not
+// modify the target as checks run concurrently
+type VulnerabilityCheck interface {
+	ID() string
+	Run(ctx context.Context, t *Target) Finding
+}
+
+// Registry holds the vulnerability checks run by a scan
+type Registry struct {
+	mu     sync.RWMutex
+	checks map[string]VulnerabilityCheck
+}
+
+// NewRegistry returns an empty registry
+func NewRegistry() *Registry {
+	return &Registry{checks: map[string]VulnerabilityCheck{}}
+}
+
+// DefaultRegistry holds the checks of ScanConfiguration
+var DefaultRegistry = NewRegistry()
+
+func init() {
+	DefaultRegistry.Register(heartbleedCheck{})
+	DefaultRegistry.Register(ccsInjectionCheck{})
+	DefaultRegistry.Register(debianWeakKeyCheck{})
+	DefaultRegistry.Register(ticketbleedCheck{})
+	DefaultRegistry.Register(ticketKeyRotationCheck{})
+	DefaultRegistry.Register(fallbackSCSVCheck{})
+	DefaultRegistry.Register(poodleCheck{})
+	DefaultRegistry.Register(tlsPoodleCheck{})
+	DefaultRegistry.Register(robotCheck{})
+	DefaultRegistry.Register(crimeCheck{})
+	DefaultRegistry.Register(insecureRenegotiationCheck{})
+	DefaultRegistry.Register(clientRenegotiationCheck{})
+}
+
+// Register adds c to the registry, replacing a check with the same id
+func (r *Registry) Register(c VulnerabilityCheck) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks[c.ID()] = c
+}
+
+// IDs returns the sorted ids of the registered checks
+func (r *Registry) IDs() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.ids()
+}
+
+// ids returns the sorted ids, r.mu must be held
+func (r *Registry) ids() []string {
+	var ids []string
+	for id := range r.checks {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// Valid reports if all ids are registered
+func (r *Registry) Valid(ids []string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, id := range ids {
+		if _, ok := r.checks[id]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// selected returns the checks in enabled, or all checks when enabled is
+// empty, without the checks in disabled
+func (r *Registry) selected(enabled []string, disabled []string) []VulnerabilityCheck {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var checks []VulnerabilityCheck
+	for _, id := range r.ids() {
+		if len(enabled) > 0 && !slices.Contains(enabled, id) {
+			continue
+		}
+		if slices.Contains(disabled, id) {
+			continue
+		}
+		checks = append(checks, r.checks[id])
+	}
+	return checks
+}
+
+// Run runs the selected checks concurrently and returns their findings
+// sorted by id. Checks not started before ctx is done fail
+func (r *Registry) Run(ctx context.Context, t *Target, enabled []string, disabled []string) []Finding {
+	var WG sync.WaitGroup
+
+	checks := r.selected(enabled, disabled)
+	findings := make([]Finding, len(checks))
+
+	for i, c := range checks {
+		WG.Add(1)
+		go func() {
+			defer WG.Done()
+
+			if err := ctx.Err(); err != nil {
+				findings[i] = Finding{ID: c.ID(), Status: statusFailed, Evidence: []string{err.Error()}}
+				return
+			}
+
+			findings[i] = c.Run(ctx, t)
+			findings[i].ID = c.ID()
+			logger.Debugf("event_id=vulnerability_check id=%s status=%s", c.ID(), findings[i].Status)
+		}()
+	}
+
+	WG.Wait()
+	return findings
+}
+
+// errorEvidence returns the error message as evidence of a failed check
+func errorEvidence(err error) []string {
+	if err == nil {
+		return nil
+	}
+	return []string{err.Error()}
+}
+
+// heartbeatEvidence is the evidence of a heartbleed finding when the
+// heartbeat extension is enabled
+const heartbeatEvidence = "heartbeat extension enabled"
+
+// heartbleedCheck checks for CVE-2014-0160
+type heartbleedCheck struct{}
+
+func (heartbleedCheck) ID() string { return "heartbleed" }
+
+func (heartbleedCheck) Run(ctx context.Context, t *Target) Finding {
+	release := t.Options.Acquire()
+	defer release()
+
+	var h heartbleed.Heartbleed
+	err := h.Check(t.Host, t.Port, t.TLSVersion)
+
+	f := Finding{Severity: SeverityHigh, Status: h.Vulnerable, Evidence: errorEvidence(err)}
+	if h.ExtensionEnabled {
+		f.Evidence = append(f.Evidence, heartbeatEvidence)
+	}
+	return f
+}
+
+// ccsInjectionCheck checks for CVE-2014-0224
+type ccsInjectionCheck struct{}
+
+func (ccsInjectionCheck) ID() string { return "ccsInjection" }
+
+func (ccsInjectionCheck) Run(ctx context.Context, t *Target) Finding {
+	release := t.Options.Acquire()
+	defer release()
+
+	var c ccs.CCSInjection
+	err := c.Check(t.Host, t.Port)
+
+	return Finding{Severity: SeverityHigh, Status: c.Vulnerable, Evidence: errorEvidence(err)}
+}
+
+// debianWeakKeyCheck checks if the RSA key of the certificate was
+// generated by the Debian OpenSSL affected by CVE-2008-0166
+type debianWeakKeyCheck struct{}
+
+func (debianWeakKeyCheck) ID() string { return "debianWeakKey" }
+
+func (debianWeakKeyCheck) Run(ctx context.Context, t *Target) Finding {
+	f := Finding{Severity: SeverityCritical, Status: statusNotApplicable}
+
+	certs := t.ConnState.PeerCertificates
+	if len(certs) == 0 {
+		return f
+	}
+	pubKey, ok := certs[0].PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return f
+	}
+
+	var w debianweakkey.DebianWeakKey
+	keySize := pubKey.Size() * 8
+	err := w.Check(keySize, fmt.Sprintf("%x", pubKey.N))
+
+	f.Status = w.Vulnerable
+	f.Evidence = append(errorEvidence(err), fmt.Sprintf("%d bit rsa key", keySize))
+	return f
+}
+
+// protocolEvidence returns the protocol a check was done with as evidence
+func protocolEvidence(protocol string) []string {
+	if protocol == "" {
+		return nil
+	}
+	return []string{"protocol " + protocol}
+}
+
+// The checks below use the ssl package, which holds a connection of
+// Target.Options for each dial
+
+// ticketbleedCheck checks for CVE-2016-9244
+type ticketbleedCheck struct{}
+
+func (ticketbleedCheck) ID() string { return "ticketbleed" }
+
+func (ticketbleedCheck) Run(ctx context.Context, t *Target) Finding {
+	supported, err := t.Supported(ctx)
+	if err != nil {
+		return Finding{Severity: SeverityHigh, Status: statusFailed, Evidence: errorEvidence(err)}
+	}
+
+	var v ssl.Ticketbleed
+	err = v.Check(t.Host, t.Port, supported, t.Options)
+
+	f := Finding{Severity: SeverityHigh, Status: v.Vulnerable, Evidence: errorEvidence(err), Details: v}
+	if v.LeakedBytes > 0 {
+		f.Evidence = append(f.Evidence, fmt.Sprintf("%d bytes leaked in the session id", v.LeakedBytes))
+	}
+	return f
+}
+
+// ticketKeyRotationCheck reports servers that encrypt every session
+// ticket with the same key, which undermines forward secrecy
+type ticketKeyRotationCheck struct{}
+
+func (ticketKeyRotationCheck) ID() string { return "ticketKeyRotation" }
+
+func (ticketKeyRotationCheck) Run(ctx context.Context, t *Target) Finding {
+	supported, err := t.Supported(ctx)
+	if err != nil {
+		return Finding{Severity: SeverityLow, Status: statusFailed, Evidence: errorEvidence(err)}
+	}
+
+	var r ssl.TicketKeyRotation
+	r.Check(t.Host, t.Port, supported, t.Options)
+
+	f := Finding{Severity: SeverityLow, Status: statusNotApplicable, Details: r}
+	switch r.Rotation {
+	case ssl.RotationStatic:
+		f.Status = statusVulnerable
+	case ssl.RotationRotated:
+		f.Status = statusNotVulnerable
+	}
+	f.Evidence = []string{fmt.Sprintf("%s ticket key of %d tickets", r.Rotation, r.Tickets)}
+	if len(r.KeyNames) > 0 {
+		f.Evidence = append(f.Evidence, "key names "+strings.Join(r.KeyNames, ", "))
+	}
+	if r.LifetimeHint > 0 {
+		f.Evidence = append(f.Evidence, fmt.Sprintf("lifetime hint %ds", r.LifetimeHint))
+	}
+	return f
+}
+
+// fallbackSCSVCheck checks for downgrade protection (RFC 7507)
+type fallbackSCSVCheck struct{}
+
+func (fallbackSCSVCheck) ID() string { return "fallbackScsv" }
+
+func (fallbackSCSVCheck) Run(ctx context.Context, t *Target) Finding {
+	supported, err := t.Supported(ctx)
+	if err != nil {
+		return Finding{Severity: SeverityMedium, Status: statusFailed, Evidence: errorEvidence(err)}
+	}
+
+	var v ssl.FallbackSCSV
+	err = v.Check(t.Host, t.Port, supported, t.Options)
+
+	return Finding{Severity: SeverityMedium, Status: v.Vulnerable, Evidence: append(errorEvidence(err), protocolEvidence(v.Protocol)...), Details: v}
+}
+
+// poodleCheck checks for SSLv3 with block ciphers (CVE-2014-3566)
+type poodleCheck struct{}
+
+func (poodleCheck) ID() string { return "poodle" }
+
+func (poodleCheck) Run(ctx context.Context, t *Target) Finding {
+	var v ssl.POODLE
+	err := v.Check(t.Host, t.Port, t.Options)
+
+	return Finding{Severity: SeverityMedium, Status: v.Vulnerable, Evidence: errorEvidence(err), Details: v}
+}
+
+// tlsPoodleCheck checks if the server ignores bad CBC padding in TLS
+type tlsPoodleCheck struct{}
+
+func (tlsPoodleCheck) ID() string { return "tlsPoodle" }
+
+func (tlsPoodleCheck) Run(ctx context.Context, t *Target) Finding {
+	var v ssl.TLSPOODLE
+	err := v.Check(t.Host, t.Port, t.Options)
+
+	return Finding{Severity: SeverityMedium, Status: v.Vulnerable, Evidence: append(errorEvidence(err), protocolEvidence(v.Protocol)...), Details: v}
+}
+
+// robotCheck checks for an RSA padding oracle (CVE-2017-13099 and others)
+type robotCheck struct{}
+
+func (robotCheck) ID() string { return "robot" }
+
+func (robotCheck) Run(ctx context.Context, t *Target) Finding {
+	var v ssl.ROBOT
+	err := v.Check(t.Host, t.Port, t.Options)
+
+	f := Finding{Severity: SeverityHigh, Status: v.Vulnerable, Evidence: append(errorEvidence(err), protocolEvidence(v.Protocol)...), Details: v}
+	if v.Oracle != "" {
+		f.Evidence = append(f.Evidence, v.Oracle+" oracle")
+	}
+	return f
+}
+
+// crimeCheck checks for TLS compression (CVE-2012-4929)
+type crimeCheck struct{}
+
+func (crimeCheck) ID() string { return "crime" }
+
+func (crimeCheck) Run(ctx context.Context, t *Target) Finding {
+	var v ssl.CRIME
+	err := v.Check(t.Host, t.Port, t.Options)
+
+	return Finding{Severity: SeverityMedium, Status: v.Vulnerable, Evidence: append(errorEvidence(err), protocolEvidence(v.Protocol)...), Details: v}
+}
+
+// insecureRenegotiationCheck checks for servers without secure
+// renegotiation (CVE-2009-3555)
+type insecureRenegotiationCheck struct{}
+
+func (insecureRenegotiationCheck) ID() string { return "insecureRenegotiation" }
+
+func (insecureRenegotiationCheck) Run(ctx context.Context, t *Target) Finding {
+	var v ssl.Renegotiation
+	err := v.CheckSecure(t.Host, t.Port, t.Options)
+
+	return Finding{Severity: SeverityMedium, Status: v.Insecure, Evidence: append(errorEvidence(err), protocolEvidence(v.Protocol)...), Details: v}
+}
+
+// clientRenegotiationCheck checks if the server renegotiates on request
+// of the client, which allows a DoS
+type clientRenegotiationCheck struct{}
+
+func (clientRenegotiationCheck) ID() string { return "clientRenegotiation" }
+
+func (clientRenegotiationCheck) Run(ctx context.Context, t *Target) Finding {
+	var v ssl.Renegotiation
+	err := v.Check(t.Host, t.Port, t.Options)
+
+	f := Finding{Severity: SeverityLow, Status: v.ClientInitiated, Evidence: append(errorEvidence(err), protocolEvidence(v.Protocol)...), Details: v}
+	if v.Detail != "" {
+		f.Evidence = append(f.Evidence, v.Detail)
+	}
+	return f
+}
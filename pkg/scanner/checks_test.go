@@ -0,0 +1,157 @@
+package scanner
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeCheck returns its status after waiting for wait, if set
+type fakeCheck struct {
+	id     string
+	status string
+	wait   *sync.WaitGroup
+}
+
+func (c fakeCheck) ID() string { return c.id }
+
+func (c fakeCheck) Run(ctx context.Context, t *Target) Finding {
+	if c.wait != nil {
+		c.wait.Done()
+		c.wait.Wait()
+	}
+	return Finding{Severity: SeverityLow, Status: c.status, Evidence: []string{t.Host}}
+}
+
+func findingIDs(findings []Finding) []string {
+	var ids []string
+	for _, f := range findings {
+		ids = append(ids, f.ID)
+	}
+	return ids
+}
+
+func TestRegistrySelection(t *testing.T) {
+	r := NewRegistry()
+	r.Register(fakeCheck{id: "b", status: "no"})
+	r.Register(fakeCheck{id: "a", status: "yes"})
+	r.Register(fakeCheck{id: "c", status: "no"})
+
+	target := &Target{Host: "localhost"}
+
+	var tests = []struct {
+		enabled  []string
+		disabled []string
+		want     []string
+	}{
+		{nil, nil, []string{"a", "b", "c"}},
+		{[]string{"c", "a"}, nil, []string{"a", "c"}},
+		{nil, []string{"b"}, []string{"a", "c"}},
+		{[]string{"a", "b"}, []string{"b"}, []string{"a"}},
+	}
+
+	for _, test := range tests {
+		got := findingIDs(r.Run(context.Background(), target, test.enabled, test.disabled))
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("enabled=%v disabled=%v: got %v, want %v", test.enabled, test.disabled, got, test.want)
+		}
+	}
+
+	findings := r.Run(context.Background(), target, []string{"a"}, nil)
+	want := Finding{ID: "a", Severity: SeverityLow, Status: "yes", Evidence: []string{"localhost"}}
+	if !reflect.DeepEqual(findings[0], want) {
+		t.Errorf("wrong finding, got: %+v", findings[0])
+	}
+}
+
+func TestRegistryRunsConcurrently(t *testing.T) {
+	var wait sync.WaitGroup
+	wait.Add(3)
+
+	r := NewRegistry()
+	for _, id := range []string{"a", "b", "c"} {
+		r.Register(fakeCheck{id: id, status: "no", wait: &wait})
+	}
+
+	done := make(chan []Finding)
+	go func() { done <- r.Run(context.Background(), &Target{}, nil, nil) }()
+
+	select {
+	case findings := <-done:
+		if len(findings) != 3 {
+			t.Errorf("wrong number of findings, got: %d", len(findings))
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("checks did not run concurrently")
+	}
+}
+
+func TestRegistryCanceled(t *testing.T) {
+	r := NewRegistry()
+	r.Register(fakeCheck{id: "a", status: "yes"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	findings := r.Run(ctx, &Target{}, nil, nil)
+	if len(findings) != 1 || findings[0].ID != "a" || findings[0].Status != statusFailed {
+		t.Errorf("canceled check should fail, got: %+v", findings)
+	}
+}
+
+func TestRegistryValid(t *testing.T) {
+	if !DefaultRegistry.Valid([]string{"heartbleed", "ccsInjection", "debianWeakKey", "ticketbleed", "robot", "clientRenegotiation"}) {
+		t.Errorf("migrated checks should be registered, got: %v", DefaultRegistry.IDs())
+	}
+	if !DefaultRegistry.Valid(nil) {
+		t.Errorf("no ids should be valid")
+	}
+	if DefaultRegistry.Valid([]string{"heartbleed", "nope"}) {
+		t.Errorf("unknown id should not be valid")
+	}
+}
+
+func TestDebianWeakKeyCheckNoRSA(t *testing.T) {
+	f := debianWeakKeyCheck{}.Run(context.Background(), &Target{})
+	if f.Status != statusNotApplicable || f.Severity != SeverityCritical {
+		t.Errorf("wrong finding, got: %+v", f)
+	}
+}
+
+func TestTargetSupported(t *testing.T) {
+	supported := map[string][]string{"TLSv1.2": {"TLS_RSA_WITH_AES_128_CBC_SHA"}}
+
+	// targets built without a scan have their supported ciphers set
+	got, err := (&Target{SupportedConfig: supported}).Supported(context.Background())
+	if err != nil || !reflect.DeepEqual(got, supported) {
+		t.Errorf("wrong supported ciphers, got: %v %v", got, err)
+	}
+
+	target := &Target{supportedReady: make(chan struct{})}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := target.Supported(ctx); err != context.DeadlineExceeded {
+		t.Errorf("waiting should end with ctx, got: %v", err)
+	}
+
+	go func() {
+		target.SupportedConfig = supported
+		close(target.supportedReady)
+	}()
+	got, err = target.Supported(context.Background())
+	if err != nil || !reflect.DeepEqual(got, supported) {
+		t.Errorf("wrong supported ciphers, got: %v %v", got, err)
+	}
+}
+
+func TestTicketbleedCheckCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	f := ticketbleedCheck{}.Run(ctx, &Target{supportedReady: make(chan struct{})})
+	if f.Status != statusFailed || f.Severity != SeverityHigh {
+		t.Errorf("wrong finding, got: %+v", f)
+	}
+}
@@ -1,9 +1,10 @@
 package scanner
 
 import (
-	"crypto/rsa"
+	"context"
 	"crypto/x509"
-	"fmt"
+	"encoding/json"
+	"slices"
 	"strings"
 	"sync"
 
@@ -55,8 +56,14 @@ type CertificateData struct {
 
 // ScanCertificate is performs tls certificate and conn checks
 func (c *CertificateData) ScanCertificate(host string, port string) {
+	c.ScanCertificateWithOptions(host, port, DefaultOptions())
+}
+
+// ScanCertificateWithOptions is performs tls certificate and conn checks
+// using the StartTLS settings of opts
+func (c *CertificateData) ScanCertificateWithOptions(host string, port string, opts Options) {
 
-	tlsConnState, _ := ssl.ConnState(host, port)
+	tlsConnState, _ := ssl.ConnStateWithOptions(host, port, opts.sslOptions())
 	certs := tlsConnState.PeerCertificates
 
 	if len(certs) == 0 {
@@ -73,45 +80,199 @@ func (c *CertificateData) ScanCertificate(host string, port string) {
 	c.HostName = host
 }
 
+// result schema versions of ConfigurationData
+const (
+	// SchemaLegacy reports supportedConfig as a flat map of cipher names
+	SchemaLegacy = 1
+	// SchemaCurrent reports supportedConfig as cipher objects
+	SchemaCurrent = 2
+)
+
 // ConfigurationData information about tls connection
 type ConfigurationData struct {
+	SchemaVersion int `json:"schemaVersion"`
 	// ChainTrusted    bool                `json:"chainTrusted"`
 	// HostName        string              `json:"hostName"`
 	// HostNameMatches bool                `json:"hostNameMatches"`
-	OCSPStapling    bool                `json:"ocspStapling"`
-	ServerHeader    string              `json:"serverHeader"`
-	SupportedConfig map[string][]string `json:"supportedConfig"`
-	Vulnerabilities Vulnerabilities     `json:"vulnerabilities"`
+	CipherPreference    map[string]ssl.CipherPreference     `json:"cipherPreference"`
+	Features            ssl.Features                        `json:"features"`
+	Intolerance         ssl.Intolerance                     `json:"intolerance"`
+	OCSPStapling        bool                                `json:"ocspStapling"`
+	ServerHeader        string                              `json:"serverHeader"`
+	SignatureAlgorithms map[string][]ssl.SignatureAlgorithm `json:"signatureAlgorithms"`
+	SupportedConfig     map[string][]ssl.CipherResult       `json:"supportedConfig"`
+	SupportedGroups     map[string][]string                 `json:"supportedGroups"`
+	Vulnerabilities     Vulnerabilities                     `json:"vulnerabilities"`
+}
+
+// MarshalJSON renders supportedConfig as cipher names and adds the
+// heartbleed, ccsinjection and debianWeakKey results only when the legacy
+// schema was requested
+func (cd ConfigurationData) MarshalJSON() ([]byte, error) {
+	type config ConfigurationData
+
+	if cd.SchemaVersion != SchemaLegacy {
+		if cd.SchemaVersion == 0 {
+			cd.SchemaVersion = SchemaCurrent
+		}
+		return json.Marshal(config(cd))
+	}
+
+	return json.Marshal(struct {
+		config
+		SupportedConfig map[string][]string   `json:"supportedConfig"`
+		Vulnerabilities legacyVulnerabilities `json:"vulnerabilities"`
+	}{
+		config:          config(cd),
+		SupportedConfig: ssl.CipherNames(cd.SupportedConfig),
+		Vulnerabilities: cd.Vulnerabilities.legacy(),
+	})
+}
+
+// legacyVulnerabilities are the vulnerabilities with the results of the
+// registered checks in the fields reported before findings. Checks that
+// were not run are omitted
+type legacyVulnerabilities struct {
+	Vulnerabilities
+	DebianWeakKey *debianweakkey.DebianWeakKey `json:"debianWeakKey,omitempty"`
+	Heartbleed    *heartbleed.Heartbleed       `json:"heartbleed,omitempty"`
+	CCSInjection  *ccs.CCSInjection            `json:"ccsinjection,omitempty"`
+}
+
+func (v Vulnerabilities) legacy() legacyVulnerabilities {
+	l := legacyVulnerabilities{Vulnerabilities: v}
+
+	for _, f := range v.Findings {
+		switch f.ID {
+		case heartbleedCheck{}.ID():
+			l.Heartbleed = &heartbleed.Heartbleed{
+				Vulnerable:       f.Status,
+				ExtensionEnabled: slices.Contains(f.Evidence, heartbeatEvidence),
+			}
+		case ccsInjectionCheck{}.ID():
+			l.CCSInjection = &ccs.CCSInjection{Vulnerable: f.Status}
+		case debianWeakKeyCheck{}.ID():
+			l.DebianWeakKey = &debianweakkey.DebianWeakKey{Vulnerable: f.Status}
+		}
+	}
+
+	return l
 }
 
 // Vulnerabilities struct of vuln results
 type Vulnerabilities struct {
-	DebianWeakKey debianweakkey.DebianWeakKey `json:"debianWeakKey"`
-	Heartbleed    heartbleed.Heartbleed       `json:"heartbleed"`
-	CCSInjection  ccs.CCSInjection            `json:"ccsinjection"`
+	// Findings are the results of the registered checks (see Registry)
+	Findings []Finding         `json:"findings"`
+	FREAK    ssl.FREAK         `json:"freak"`
+	Logjam   ssl.Logjam        `json:"logjam"`
+	WeakDH   ssl.WeakDH        `json:"weakDh"`
+	DROWN    ssl.DROWN         `json:"drown"`
+	SWEET32  ssl.CipherFinding `json:"sweet32"`
+	BEAST    ssl.CipherFinding `json:"beast"`
+	Lucky13  ssl.CipherFinding `json:"lucky13"`
+	RC4      ssl.CipherFinding `json:"rc4"`
+	BREACH   ssl.BREACH        `json:"breach"`
+}
+
+// Options controls how a configuration scan is performed
+type Options struct {
+	// Concurrency is the maximum number of connections in flight
+	// against the target, shared by every stage of the scan
+	Concurrency int
+	// Mode is the cipher enumeration mode (see ssl.ModeExhaustive
+	// and ssl.ModeElimination)
+	Mode string
+	// SchemaVersion is the result schema (SchemaLegacy or SchemaCurrent)
+	SchemaVersion int
+	// DROWNPorts are other ports of the host checked for SSLv2 with the
+	// same RSA key, e.g. 25 for SMTP
+	DROWNPorts []string
+	// Checks are the ids of the registered checks to run, all checks are
+	// run when empty
+	Checks []string
+	// DisabledChecks are the ids of the registered checks to skip
+	DisabledChecks []string
+	// XMPPDomain is the domain sent in the XMPP stream header when it
+	// differs from host
+	XMPPDomain string
+	// Service is the protocol spoken before the TLS handshake, it is
+	// detected from the port when empty (see utils.GetService)
+	Service string
+}
+
+// DefaultOptions returns the options used by ScanConfiguration
+func DefaultOptions() Options {
+	return Options{
+		Concurrency:   ssl.DefaultConcurrency,
+		Mode:          ssl.ModeElimination,
+		SchemaVersion: SchemaCurrent,
+	}
+}
+
+// sslOptions returns the options passed to the ssl package
+func (o Options) sslOptions() ssl.Options {
+	return ssl.Options{
+		Concurrency: o.Concurrency,
+		Mode:        o.Mode,
+		StartTLS:    ssl.StartTLSOptions{Service: o.Service, XMPPDomain: o.XMPPDomain},
+	}
 }
 
 // ScanConfiguration is performs tls certificate and conn checks
 func (cd *ConfigurationData) ScanConfiguration(host string, port string) {
+	cd.ScanConfigurationWithOptions(host, port, DefaultOptions())
+}
+
+// ScanConfigurationWithOptions performs tls certificate and conn checks
+// using the provided options
+func (cd *ConfigurationData) ScanConfigurationWithOptions(host string, port string, opts Options) {
+	cd.ScanConfigurationContext(context.Background(), host, port, opts)
+}
+
+// ScanConfigurationContext performs tls certificate and conn checks using
+// the provided options. Registered checks are not started once ctx is done
+func (cd *ConfigurationData) ScanConfigurationContext(ctx context.Context, host string, port string, opts Options) {
 	var WG sync.WaitGroup
 	var mutex = &sync.Mutex{}
-	var service = utils.GetService(port)
 
-	tlsConnState, tlsVers := ssl.ConnState(host, port)
+	cd.SchemaVersion = opts.SchemaVersion
+
+	// every stage of the scan shares the concurrency limit
+	sslOpts := opts.sslOptions().Limit()
+	service := sslOpts.StartTLS.ServiceFor(port)
+
+	tlsConnState, tlsVers := ssl.ConnStateWithOptions(host, port, sslOpts)
 	certs := tlsConnState.PeerCertificates
+
+	// postgres servers only accepting direct tls negotiate the postgresql protocol
+	if tlsConnState.NegotiatedProtocol == ssl.PostgresALPN {
+		sslOpts.StartTLS.PostgresDirectTLS = true
+	}
 	ocspStapling := tlsConnState.OCSPResponse
 
 	if len(certs) == 0 {
+		// servers with standard rdp security do not offer tls at all
+		if service == "rdp" {
+			cd.Features.RDPSecurity, _ = ssl.RDPSecurity(host, port, sslOpts)
+		}
 		logger.Debugf("event_id=no_certs_found host=%s port=%s", host, port)
 		return
 	}
 
 	// collect data about the connection in general
-	if service == "https" || strings.HasSuffix(service, "SSL") {
-		cd.ServerHeader, _ = utils.GetHTTPHeader(host, port, "Server")
-	} else {
+	var encoding string
+	var err error
+	switch {
+	case service == "https" || strings.HasSuffix(service, "SSL"):
+		cd.ServerHeader, encoding, err = utils.GetHTTPHeader(host, port, "Server")
+	case service == "mysql":
+		cd.ServerHeader, _ = ssl.MySQLVersion(host, port)
+	case service == "postgres", service == "ldap", service == "rdp", strings.HasPrefix(service, "xmpp"):
+		// these servers wait for the client to speak first
+	default:
 		cd.ServerHeader, _ = tcputils.GetTCPHeader(host, port)
 	}
+	cd.Vulnerabilities.BREACH.Check(service == "https", encoding, err)
 	// cd.HostNameMatches = certutil.VerifyHostname(certs[0], host)
 	// cd.ChainTrusted = certutil.IsTrusted(certs, host)
 	// cd.HostName = host
@@ -119,26 +280,50 @@ func (cd *ConfigurationData) ScanConfiguration(host string, port string) {
 		cd.OCSPStapling = true
 	}
 
+	target := &Target{Host: host, Port: port, ConnState: tlsConnState, TLSVersion: tlsVers, Options: sslOpts, supportedReady: make(chan struct{})}
+
 	WG.Add(1)
 	go func() {
 		keyType := certs[0].PublicKeyAlgorithm.String()
+		supportedConfig := ssl.CheckWithOptions(host, port, keyType, sslOpts)
+		// registered checks waiting in Target.Supported continue
+		target.SupportedConfig = supportedConfig
+		close(target.supportedReady)
+
+		cipherPreference := ssl.CipherOrder(host, port, supportedConfig, sslOpts)
+		supportedGroups := ssl.Groups(host, port, supportedConfig, sslOpts)
+		cipherResults := ssl.KeyExchangeParams(host, port, supportedConfig, sslOpts)
+		signatureAlgorithms := ssl.SignatureAlgorithms(host, port, supportedConfig, sslOpts)
+		features := ssl.CheckFeatures(host, port, supportedConfig, sslOpts)
+		intolerance := ssl.CheckIntolerance(host, port, sslOpts)
 		mutex.Lock()
-		cd.SupportedConfig = ssl.Check(host, port, keyType)
+		cd.SupportedConfig = cipherResults
+		cd.CipherPreference = cipherPreference
+		cd.SupportedGroups = supportedGroups
+		cd.SignatureAlgorithms = signatureAlgorithms
+		cd.Features = features
+		cd.Intolerance = intolerance
 		mutex.Unlock()
 		WG.Done()
 	}()
 
-	cd.Vulnerabilities.Heartbleed.Check(host, port, tlsVers)
+	cd.Vulnerabilities.Findings = DefaultRegistry.Run(ctx, target, opts.Checks, opts.DisabledChecks)
 
-	cd.Vulnerabilities.CCSInjection.Check(host, port)
+	WG.Wait()
 
-	if certs[0].PublicKeyAlgorithm.String() == "RSA" {
-		pubKey := certs[0].PublicKey.(*rsa.PublicKey)
-		keySize := pubKey.Size() * 8
-		modulus := fmt.Sprintf("%x", pubKey.N)
-		cd.Vulnerabilities.DebianWeakKey.Check(keySize, modulus)
-	}
+	// verdicts derived from the supported ciphers
+	cd.Vulnerabilities.FREAK.Check(cd.SupportedConfig)
+	cd.Vulnerabilities.Logjam.Check(cd.SupportedConfig)
+	cd.Vulnerabilities.WeakDH.Check(cd.SupportedConfig)
+	cd.Vulnerabilities.DROWN.Check(cd.SupportedConfig)
 
-	WG.Wait()
+	if len(opts.DROWNPorts) > 0 && certs[0].PublicKeyAlgorithm == x509.RSA {
+		cd.Vulnerabilities.DROWN.CrossCheck(host, opts.DROWNPorts, certutil.PublicKeyFingerprint(certs[0]), sslOpts)
+	}
 
+	findings := ssl.CipherFindings(ssl.CipherNames(cd.SupportedConfig))
+	cd.Vulnerabilities.SWEET32 = findings[ssl.RuleSWEET32]
+	cd.Vulnerabilities.BEAST = findings[ssl.RuleBEAST]
+	cd.Vulnerabilities.Lucky13 = findings[ssl.RuleLucky13]
+	cd.Vulnerabilities.RC4 = findings[ssl.RuleRC4]
 }
@@ -2,12 +2,16 @@ package scanner
 
 import (
 	"crypto/x509"
+	"encoding/json"
 	"encoding/pem"
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/jsandas/tlstools/pkg/ssl"
 )
 
 const (
@@ -151,6 +155,10 @@ func TestScanConfiguration(t *testing.T) {
 	if len(cd.SupportedConfig) != 2 {
 		t.Errorf("wrong config length, got: %d, want: %d.", len(cd.SupportedConfig), 2)
 	}
+
+	if ids := findingIDs(cd.Vulnerabilities.Findings); !reflect.DeepEqual(ids, DefaultRegistry.IDs()) {
+		t.Errorf("wrong findings, got: %v", ids)
+	}
 }
 
 func TestScanConfigurationNoTLS(t *testing.T) {
@@ -176,3 +184,75 @@ func TestScanConfigurationNoTLS(t *testing.T) {
 		t.Errorf("server should not have tls")
 	}
 }
+
+func TestConfigurationDataSchema(t *testing.T) {
+	var cd = ConfigurationData{
+		SupportedConfig: map[string][]ssl.CipherResult{
+			"TLSv1.2": {{CodePoint: "0x002F", Name: "TLS_RSA_WITH_AES_128_CBC_SHA", Bits: 128}},
+		},
+		Vulnerabilities: Vulnerabilities{Findings: []Finding{
+			{ID: "ccsInjection", Status: "no"},
+			{ID: "heartbleed", Status: "no", Evidence: []string{heartbeatEvidence}},
+		}},
+	}
+
+	b, err := json.Marshal(cd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `"schemaVersion":2`) || !strings.Contains(string(b), `"codePoint":"0x002F"`) {
+		t.Errorf("wrong current schema output, got: %s", b)
+	}
+
+	cd.SchemaVersion = SchemaLegacy
+	b, err = json.Marshal(cd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `"supportedConfig":{"TLSv1.2":["TLS_RSA_WITH_AES_128_CBC_SHA"]}`) {
+		t.Errorf("wrong legacy schema output, got: %s", b)
+	}
+	for _, field := range []string{`"heartbleed":{"vulnerable":"no","extension":true}`, `"ccsinjection":{"vulnerable":"no"}`, `"findings":[`} {
+		if !strings.Contains(string(b), field) {
+			t.Errorf("legacy schema output is missing %s, got: %s", field, b)
+		}
+	}
+	if strings.Contains(string(b), `"debianWeakKey"`) {
+		t.Errorf("checks that were not run should be omitted, got: %s", b)
+	}
+}
+
+func TestScanConfigurationRDPStandard(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	// X.224 Connection Confirm with RDP_NEG_FAILURE SSL_NOT_ALLOWED_BY_SERVER
+	confirm := []byte{0x03, 0x00, 0x00, 0x13, 0x0e, 0xd0, 0x00, 0x00, 0x12, 0x34, 0x00, 0x03, 0x00, 0x08, 0x00, 0x02, 0x00, 0x00, 0x00}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				conn.Read(make([]byte, 64))
+				conn.Write(confirm)
+			}(conn)
+		}
+	}()
+
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	opts := DefaultOptions()
+	opts.Service = "rdp"
+
+	var cd ConfigurationData
+	cd.ScanConfigurationWithOptions("127.0.0.1", port, opts)
+
+	if cd.Features.RDPSecurity != ssl.RDPSecurityStandard {
+		t.Errorf("wrong rdp security, got: %s", cd.Features.RDPSecurity)
+	}
+}
@@ -1,118 +1,306 @@
 package ssl
 
 import (
+	"net"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
 	"github.com/jsandas/etls"
 	logger "github.com/jsandas/gologger"
 )
 
+// DefaultConcurrency is the number of handshakes run in parallel
+// against a single target when no limit is provided
+const DefaultConcurrency = 8
+
+// MaxConcurrency is the highest concurrency accepted by the API, higher
+// values turn a scan into a flood of connections against the target
+const MaxConcurrency = 32
+
+// Cipher enumeration modes
+const (
+	// ModeExhaustive offers a single cipher per handshake
+	ModeExhaustive = "exhaustive"
+	// ModeElimination offers all remaining candidates per handshake and
+	// removes the cipher picked by the server until it refuses
+	ModeElimination = "elimination"
+)
+
+// Options controls how protocols and ciphers are enumerated
+type Options struct {
+	// Concurrency is the maximum number of handshakes in flight
+	// against a single target. Every probe passed options returned by
+	// Limit counts against the same limit, otherwise each probe only
+	// limits its own handshakes
+	Concurrency int
+	// Mode is the cipher enumeration mode
+	Mode string
+	// StartTLS is passed to StartTLS on every connection
+	StartTLS StartTLSOptions
+
+	// slots holds a token for each connection in flight (see Limit)
+	slots chan struct{}
+}
+
+// DefaultOptions returns the options used by Check
+func DefaultOptions() Options {
+	return Options{
+		Concurrency: DefaultConcurrency,
+		Mode:        ModeElimination,
+	}
+}
+
+// Limit returns a copy of o whose connections, and those of every copy
+// made from it, count against a single limit of o.Concurrency
+func (o Options) Limit() Options {
+	o.slots = make(chan struct{}, o.workers())
+	return o
+}
+
+// Acquire reserves one of the connections of o for a probe that dials on
+// its own and returns the func releasing it
+func (o Options) Acquire() (release func()) {
+	if o.slots == nil {
+		return func() {}
+	}
+
+	o.slots <- struct{}{}
+	return func() { <-o.slots }
+}
+
+// workers returns the number of workers of the pool of a probe
+func (o Options) workers() int {
+	if o.Concurrency < 1 {
+		return 1
+	}
+	return o.Concurrency
+}
+
+// dial opens a tcp connection to host:port that holds one of the
+// connections of o until it is closed
+func (o Options) dial(host string, port string, timeout time.Duration) (net.Conn, error) {
+	release := o.Acquire()
+
+	conn, err := net.DialTimeout("tcp", host+":"+port, timeout)
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	return &limitedConn{Conn: conn, release: release}, nil
+}
+
+// limitedConn releases its connection slot when closed
+type limitedConn struct {
+	net.Conn
+	once    sync.Once
+	release func()
+}
+
+func (c *limitedConn) Close() error {
+	err := c.Conn.Close()
+	c.once.Do(c.release)
+	return err
+}
+
+// ValidMode returns true if m is a known enumeration mode
+func ValidMode(m string) bool {
+	return m == ModeExhaustive || m == ModeElimination
+}
+
 // Check performs tls handshakes to find support
 // ciphers and protocols
 func Check(host string, port string, keyType string) map[string][]string {
-	// var WG sync.WaitGroup
-	// var mutex = &sync.Mutex{}
-	supportedConfig := make(map[string][]string)
-	var protoList []int
-	var cipherList []string
+	return CheckWithOptions(host, port, keyType, DefaultOptions())
+}
 
-	protoList = getProtocols(host, port)
+// CheckWithOptions performs tls handshakes to find supported ciphers
+// and protocols using at most opts.Concurrency connections at a time
+func CheckWithOptions(host string, port string, keyType string, opts Options) map[string][]string {
+	var mutex = &sync.Mutex{}
+	supportedConfig := make(map[string][]string)
+	found := make(map[int][]uint16)
 
-	for i := range protoList {
-		// WG.Add(1)
-		// go func(p int) {
-		pname := protocolVersionMap[protoList[i]]
+	protoList := getProtocols(host, port, opts)
 
-		cipherList = getCiphers(host, port, protoList[i], keyType)
+	var jobs []func()
+	for _, p := range protoList {
+		candidates := getCandidates(p, keyType)
 
-		if len(cipherList) > 0 {
-			// mutex.Lock()
-			supportedConfig[pname] = cipherList
-			// mutex.Unlock()
+		if opts.Mode == ModeElimination {
+			jobs = append(jobs, func() {
+				ciphers := eliminateCiphers(host, port, p, candidates, opts)
+				mutex.Lock()
+				found[p] = ciphers
+				mutex.Unlock()
+			})
+			continue
 		}
 
-		// 	WG.Done()
-		// }(i)
+		for _, c := range candidates {
+			jobs = append(jobs, func() {
+				logger.Debugf("testing cipher: %s | protocol: %d", cipherSuites[c].name, p)
+				if !connect(host, port, p, c, opts) {
+					return
+				}
+				mutex.Lock()
+				found[p] = append(found[p], c)
+				mutex.Unlock()
+			})
+		}
 	}
-	// WG.Wait()
 
 	// Check sslv2 support
-	sslv2 := sslv2Check(host, port)
-	if val, ok := sslv2["SSLv2"]; ok {
-		supportedConfig["SSLv2"] = val
+	jobs = append(jobs, func() {
+		sslv2 := sslv2Check(host, port, opts)
+		if val, ok := sslv2["SSLv2"]; ok {
+			mutex.Lock()
+			supportedConfig["SSLv2"] = val
+			mutex.Unlock()
+		}
+	})
+
+	runPool(opts.workers(), jobs)
+
+	for p, ciphers := range found {
+		if len(ciphers) == 0 {
+			continue
+		}
+		sort.Slice(ciphers, func(i, j int) bool { return ciphers[i] < ciphers[j] })
+		supportedConfig[protocolVersionMap[p]] = cipherStrList(ciphers)
 	}
 
 	logger.Debugf("supported configurations: %v", supportedConfig)
 	return supportedConfig
 }
 
-func connect(host string, port string, p int, c uint16) bool {
-	var cipher uint16 = c
+// runPool runs jobs on at most n workers and waits for all of them to finish
+func runPool(n int, jobs []func()) {
+	var WG sync.WaitGroup
+
+	queue := make(chan func())
 
-	return serverDial(host, port, p, []uint16{cipher})
+	if n > len(jobs) {
+		n = len(jobs)
+	}
+
+	for i := 0; i < n; i++ {
+		WG.Add(1)
+		go func() {
+			defer WG.Done()
+			for job := range queue {
+				job()
+			}
+		}()
+	}
+
+	for _, job := range jobs {
+		queue <- job
+	}
+	close(queue)
+
+	WG.Wait()
 }
 
-// getProtocols returns list of support TLS protocols
-func getProtocols(host string, port string) []int {
-	var protoList []int
+// cipherIDs returns the keys of cipherSuites in ascending order
+func cipherIDs() []uint16 {
+	var ids []uint16
+	for id := range cipherSuites {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
 
-	for p := range protocolVersionMap {
+	return ids
+}
+
+// getCandidates returns the ciphers worth testing for protocol/keyType
+func getCandidates(protocol int, keyType string) []uint16 {
+	var candidates []uint16
+	for _, c := range cipherIDs() {
+		if proceed(cipherSuites[c], protocol, keyType) {
+			candidates = append(candidates, c)
+		}
+	}
 
-		supported := serverDial(host, port, p, nil)
+	return candidates
+}
 
-		if supported {
-			protoList = append(protoList, p)
+// protocolCiphers returns every known cipher that is valid for protocol p
+func protocolCiphers(p int) []uint16 {
+	var ciphers []uint16
+	for _, c := range cipherIDs() {
+		if proceed(cipherSuites[c], p, cipherSuites[c].authentication) {
+			ciphers = append(ciphers, c)
 		}
 	}
 
-	logger.Debugf("supported protocols: %v", protoList)
-	return protoList
+	return ciphers
 }
 
-// getCiphers returns list of support TLS ciphers
-func getCiphers(host string, port string, protocol int, keyType string) []string {
-	var cipherList []string
-	// var tmpCipherList []uint16
-	// var cWG sync.WaitGroup
-	// var cmtex = &sync.Mutex{}
+// eliminateCiphers offers all candidates in one handshake and removes the
+// cipher picked by the server each round until the server refuses. If the
+// server picks a cipher that was not offered the remaining candidates are
+// tested one at a time.
+func eliminateCiphers(host string, port string, protocol int, candidates []uint16, opts Options) []uint16 {
+	var supported []uint16
 
-	// cipherChan := make(chan string)
-	for i, c := range cipherSuites {
-		// cWG.Add(1)
-		// go func(c cipherSuite) {
-		var supported bool
+	remaining := append([]uint16(nil), candidates...)
 
-		if !proceed(c, protocol, keyType) {
-			// cWG.Done()
-			// return
-			continue
+	for len(remaining) > 0 {
+		picked, ok := serverDialCipher(host, port, protocol, remaining, opts)
+		if !ok {
+			break
 		}
-		logger.Debugf("testing cipher: %s | protocol: %d", c.name, protocol)
-		supported = connect(host, port, protocol, i)
 
-		if supported {
-			cipherList = append(cipherList, c.name)
-			// cipherChan <- c.name
+		i := slices.Index(remaining, picked)
+		if i < 0 {
+			logger.Debugf("event_id=unexpected_cipher proto=%s cipher=%#04x", protocolVersionMap[protocol], picked)
+			for _, c := range remaining {
+				if connect(host, port, protocol, c, opts) {
+					supported = append(supported, c)
+				}
+			}
+			break
 		}
-		// 	cWG.Done()
-		// }(c)
-
-		// go func() {
-		// 	for s := range cipherChan {
-		// 		cmtex.Lock()
-		// 		cipherList = append(cipherList, s)
-		// 		cmtex.Unlock()
-		// 	}
-		// }()
-		// cWG.Wait()
-	}
-
-	// sort.Ints(tmpCipherList)
-	// for i := range tmpCipherList {
-	// 	cInt := tmpCipherList[i]
-	// 	cipherList = append(cipherList, cipherSuitesMap[cInt].name)
-	// }
-	logger.Debugf("supported ciphers %s: %v", protocolVersionMap[protocol], cipherList)
-
-	return cipherList
+
+		logger.Debugf("event_id=cipher_eliminated proto=%s cipher=%s", protocolVersionMap[protocol], cipherSuites[picked].name)
+		supported = append(supported, picked)
+		remaining = slices.Delete(remaining, i, i+1)
+	}
+
+	return supported
+}
+
+func connect(host string, port string, p int, c uint16, opts Options) bool {
+	var cipher uint16 = c
+
+	return serverDial(host, port, p, []uint16{cipher}, opts)
+}
+
+// getProtocols returns list of support TLS protocols
+func getProtocols(host string, port string, opts Options) []int {
+	var mutex = &sync.Mutex{}
+	var protoList []int
+	var jobs []func()
+
+	for p := range protocolVersionMap {
+		jobs = append(jobs, func() {
+			if !serverDial(host, port, p, nil, opts) {
+				return
+			}
+			mutex.Lock()
+			protoList = append(protoList, p)
+			mutex.Unlock()
+		})
+	}
+
+	runPool(opts.workers(), jobs)
+	sort.Ints(protoList)
+
+	logger.Debugf("supported protocols: %v", protoList)
+	return protoList
 }
 
 // proceed determines if check should proceed
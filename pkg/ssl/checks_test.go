@@ -2,10 +2,16 @@ package ssl
 
 import (
 	"crypto/tls"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/jsandas/etls"
 )
@@ -28,13 +34,13 @@ func TestConnect(t *testing.T) {
 	port := s[1]
 
 	// SSLv3/TLS_RSA_WITH_AES_128_CBC_SHA no go
-	b1 := connect(host, port, etls.VersionSSL30, etls.TLS_RSA_WITH_AES_128_CBC_SHA)
+	b1 := connect(host, port, etls.VersionSSL30, etls.TLS_RSA_WITH_AES_128_CBC_SHA, DefaultOptions())
 	if b1 {
 		t.Errorf("should not have connected, got: %v, want: %v.", b1, false)
 	}
 
 	// TLSv1.2/TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384 go
-	b2 := connect(host, port, etls.VersionTLS12, etls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384)
+	b2 := connect(host, port, etls.VersionTLS12, etls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384, DefaultOptions())
 	if !b2 {
 		t.Errorf("should have connected, got: %v, want: %v.", b2, true)
 	}
@@ -72,3 +78,190 @@ func TestCheck(t *testing.T) {
 		t.Errorf("protocol count incorrect, got: %d, want: %d.", len(l), 2)
 	}
 }
+
+func TestCheckWithOptionsConcurrency(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Write([]byte("Hello"))
+	}))
+	server.TLS = &tls.Config{
+		MinVersion: uint16(etls.VersionTLS12),
+		MaxVersion: uint16(etls.VersionTLS12),
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	host, port, _ := net.SplitHostPort(strings.Replace(server.URL, "https://", "", -1))
+
+	serial := CheckWithOptions(host, port, "RSA", Options{Concurrency: 1})
+	parallel := CheckWithOptions(host, port, "RSA", Options{Concurrency: 16})
+
+	if !reflect.DeepEqual(serial, parallel) {
+		t.Errorf("results differ, serial: %v, parallel: %v", serial, parallel)
+	}
+
+	ciphers := parallel["TLSv1.2"]
+	if len(ciphers) == 0 {
+		t.Fatalf("no ciphers found for TLSv1.2")
+	}
+
+	for i := 1; i < len(ciphers); i++ {
+		if cipherNameID(ciphers[i-1]) > cipherNameID(ciphers[i]) {
+			t.Errorf("ciphers not sorted, got: %v", ciphers)
+		}
+	}
+}
+
+func TestRunPool(t *testing.T) {
+	var running, peak, done int32
+	var jobs []func()
+
+	for i := 0; i < 50; i++ {
+		jobs = append(jobs, func() {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			atomic.AddInt32(&done, 1)
+		})
+	}
+
+	runPool(4, jobs)
+
+	if done != 50 {
+		t.Errorf("not all jobs ran, got: %d, want: %d", done, 50)
+	}
+	if peak > 4 {
+		t.Errorf("too many concurrent jobs, got: %d, want: <= %d", peak, 4)
+	}
+}
+
+func TestOptionsLimit(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go io.Copy(io.Discard, conn)
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(l.Addr().String())
+
+	var open, peak int32
+	probe := func(opts Options) []func() {
+		var jobs []func()
+		for i := 0; i < 10; i++ {
+			jobs = append(jobs, func() {
+				conn, err := opts.dial(host, port, time.Second)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				n := atomic.AddInt32(&open, 1)
+				for {
+					p := atomic.LoadInt32(&peak)
+					if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+				atomic.AddInt32(&open, -1)
+				conn.Close()
+			})
+		}
+		return jobs
+	}
+
+	// two probes with their own pools share the limit of a copy
+	opts := Options{Concurrency: 2}.Limit()
+	copied := opts
+	copied.Mode = ModeExhaustive
+
+	var wg sync.WaitGroup
+	for _, o := range []Options{opts, copied} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			runPool(8, probe(o))
+		}()
+	}
+	wg.Wait()
+
+	if peak > 2 {
+		t.Errorf("too many concurrent connections, got: %d, want: <= %d", peak, 2)
+	}
+}
+
+func cipherNameID(name string) uint16 {
+	for id, c := range cipherSuites {
+		if c.name == name {
+			return id
+		}
+	}
+	return 0
+}
+
+// countingListener counts accepted connections
+type countingListener struct {
+	net.Listener
+	accepted int32
+}
+
+func (l *countingListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err == nil {
+		atomic.AddInt32(&l.accepted, 1)
+	}
+	return conn, err
+}
+
+func TestCheckElimination(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Write([]byte("Hello"))
+	}))
+	server.TLS = &tls.Config{
+		MinVersion: uint16(etls.VersionTLS12),
+		MaxVersion: uint16(etls.VersionTLS12),
+		CipherSuites: []uint16{
+			etls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+			etls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+			etls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA,
+		},
+	}
+	listener := &countingListener{Listener: server.Listener}
+	server.Listener = listener
+	server.StartTLS()
+	defer server.Close()
+
+	host, port, _ := net.SplitHostPort(strings.Replace(server.URL, "https://", "", -1))
+
+	exhaustive := CheckWithOptions(host, port, "RSA", Options{Concurrency: 4, Mode: ModeExhaustive})
+	exhaustiveCount := atomic.SwapInt32(&listener.accepted, 0)
+
+	elimination := CheckWithOptions(host, port, "RSA", Options{Concurrency: 4, Mode: ModeElimination})
+	eliminationCount := atomic.LoadInt32(&listener.accepted)
+
+	if !reflect.DeepEqual(exhaustive, elimination) {
+		t.Errorf("results differ, exhaustive: %v, elimination: %v", exhaustive, elimination)
+	}
+
+	if len(elimination["TLSv1.2"]) != 3 {
+		t.Errorf("wrong cipher count, got: %v, want: %d", elimination["TLSv1.2"], 3)
+	}
+
+	if eliminationCount >= exhaustiveCount {
+		t.Errorf("elimination should use fewer handshakes, got: %d, exhaustive: %d", eliminationCount, exhaustiveCount)
+	}
+}
@@ -0,0 +1,100 @@
+package ssl
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/jsandas/etls"
+	logger "github.com/jsandas/gologger"
+)
+
+// names of the findings derived from the supported ciphers
+const (
+	RuleSWEET32 = "sweet32"
+	RuleBEAST   = "beast"
+	RuleLucky13 = "lucky13"
+	RuleRC4     = "rc4"
+)
+
+// CipherFinding is a vulnerability identified from the supported ciphers
+type CipherFinding struct {
+	Vulnerable string `json:"vulnerable"`
+	// Evidence lists the offending ciphers as "protocol cipher"
+	Evidence []string `json:"evidence,omitempty"`
+}
+
+// cipherRule flags the ciphers of a protocol by their metadata
+type cipherRule struct {
+	name  string
+	match func(p int, c cipherSuite) bool
+}
+
+var cipherRules = []cipherRule{
+	{
+		// 64-bit block ciphers (CVE-2016-2183)
+		name: RuleSWEET32,
+		match: func(p int, c cipherSuite) bool {
+			for _, enc := range []string{"3DES", "DES", "IDEA", "RC2"} {
+				if strings.HasPrefix(c.encryption, enc+"(") {
+					return true
+				}
+			}
+			return false
+		},
+	},
+	{
+		// CBC with the predictable IV of SSLv3 and TLS 1.0 (CVE-2011-3389)
+		name: RuleBEAST,
+		match: func(p int, c cipherSuite) bool {
+			return p <= etls.VersionTLS10 && isCBC(c)
+		},
+	},
+	{
+		// CBC with MAC-then-encrypt (CVE-2013-0169)
+		name: RuleLucky13,
+		match: func(p int, c cipherSuite) bool {
+			return isCBC(c)
+		},
+	},
+	{
+		name: RuleRC4,
+		match: func(p int, c cipherSuite) bool {
+			return strings.HasPrefix(c.encryption, "RC4")
+		},
+	},
+}
+
+// CipherFindings applies the cipher rules to the supported ciphers of
+// every protocol. SSLv2 ciphers are not covered (see DROWN)
+func CipherFindings(supported map[string][]string) map[string]CipherFinding {
+	findings := make(map[string]CipherFinding)
+
+	for _, rule := range cipherRules {
+		var evidence []string
+
+		for _, pname := range slices.Sorted(maps.Keys(supported)) {
+			p, ok := protocolVersion(pname)
+			if !ok {
+				continue
+			}
+			for _, id := range cipherIDList(supported[pname]) {
+				c := cipherSuites[id]
+				if rule.match(p, c) {
+					evidence = append(evidence, fmt.Sprintf("%s %s", pname, c.name))
+				}
+			}
+		}
+
+		findings[rule.name] = CipherFinding{Vulnerable: verdict(evidence), Evidence: evidence}
+	}
+
+	logger.Debugf("event_id=cipher_findings results=%v", findings)
+	return findings
+}
+
+// isCBC reports if cipher c uses a block cipher in CBC mode with HMAC
+func isCBC(c cipherSuite) bool {
+	return c.messageAuthCode != "AEAD" && c.encryption != "None" && !strings.HasPrefix(c.encryption, "RC4")
+}
@@ -0,0 +1,46 @@
+package ssl
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCipherFindings(t *testing.T) {
+	supported := map[string][]string{
+		"SSLv2":   {"SSL2_DES_64_CBC_WITH_MD5"},
+		"TLSv1.0": {"TLS_RSA_WITH_3DES_EDE_CBC_SHA", "TLS_RSA_WITH_RC4_128_SHA", "TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA"},
+		"TLSv1.2": {"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", "TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA"},
+		"TLSv1.3": {"TLS_AES_128_GCM_SHA256"},
+	}
+
+	want := map[string]CipherFinding{
+		RuleSWEET32: {Vulnerable: vulnerable, Evidence: []string{"TLSv1.0 TLS_RSA_WITH_3DES_EDE_CBC_SHA"}},
+		RuleBEAST: {Vulnerable: vulnerable, Evidence: []string{
+			"TLSv1.0 TLS_RSA_WITH_3DES_EDE_CBC_SHA",
+			"TLSv1.0 TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA",
+		}},
+		RuleLucky13: {Vulnerable: vulnerable, Evidence: []string{
+			"TLSv1.0 TLS_RSA_WITH_3DES_EDE_CBC_SHA",
+			"TLSv1.0 TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA",
+			"TLSv1.2 TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA",
+		}},
+		RuleRC4: {Vulnerable: vulnerable, Evidence: []string{"TLSv1.0 TLS_RSA_WITH_RC4_128_SHA"}},
+	}
+
+	if got := CipherFindings(supported); !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong findings, got: %+v, want: %+v", got, want)
+	}
+}
+
+func TestCipherFindingsModern(t *testing.T) {
+	supported := map[string][]string{
+		"TLSv1.2": {"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", "TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256"},
+		"TLSv1.3": {"TLS_AES_128_GCM_SHA256"},
+	}
+
+	for name, f := range CipherFindings(supported) {
+		if f.Vulnerable != notVulnerable || len(f.Evidence) != 0 {
+			t.Errorf("%s: wrong finding, got: %+v", name, f)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package ssl
+
+import (
+	"github.com/jsandas/etls"
+	logger "github.com/jsandas/gologger"
+)
+
+// compressionDeflate is the DEFLATE compression method (RFC 3749)
+const compressionDeflate = 1
+
+// CRIME checks if the server compresses TLS records (CVE-2012-4929)
+type CRIME struct {
+	Vulnerable string `json:"vulnerable"`
+	// Protocol is the protocol DEFLATE was negotiated with
+	Protocol string `json:"protocol,omitempty"`
+}
+
+// Check offers DEFLATE and the null method in a ClientHello for each
+// protocol up to TLS 1.2 until the server answers. TLS 1.3 removed
+// compression, so servers without an older protocol are not applicable
+func (v *CRIME) Check(host string, port string, opts Options) error {
+	var err error
+
+	for _, p := range []int{etls.VersionTLS12, etls.VersionTLS11, etls.VersionTLS10, etls.VersionSSL30} {
+		hello := newClientHello(host, p, protocolCiphers(p))
+		hello.compression = []uint8{compressionDeflate, 0}
+		if p > etls.VersionSSL30 {
+			hello.setGroups(kexGroups(p))
+		}
+
+		var flight *serverFlight
+		flight, err = rawHandshake(host, port, hello, opts)
+		if flight == nil || flight.hello == nil {
+			continue
+		}
+
+		v.Protocol = protocolVersionMap[int(flight.hello.version)]
+		v.Vulnerable = notVulnerable
+		if flight.hello.compression == compressionDeflate {
+			logger.Debugf("event_id=crime_deflate_selected proto=%s", v.Protocol)
+			v.Vulnerable = vulnerable
+		}
+		return nil
+	}
+
+	if err == nil || isRefusal(err) {
+		v.Vulnerable = notApplicable
+		return nil
+	}
+
+	v.Vulnerable = testFailed
+	return err
+}
+
+// BREACH reports if an HTTPS server compresses responses, which is the
+// precondition for BREACH (CVE-2013-3587)
+type BREACH struct {
+	Vulnerable string `json:"vulnerable"`
+	// Encoding is the Content-Encoding of the response
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// Check derives the verdict from the response to a request sent with
+// Accept-Encoding (see utils.GetHTTPHeader)
+func (v *BREACH) Check(https bool, encoding string, err error) {
+	v.Encoding = encoding
+
+	switch {
+	case !https:
+		v.Vulnerable = notApplicable
+	case err != nil:
+		v.Vulnerable = testFailed
+	case encoding != "":
+		v.Vulnerable = vulnerable
+	default:
+		v.Vulnerable = notVulnerable
+	}
+}
@@ -0,0 +1,71 @@
+package ssl
+
+import (
+	"crypto/tls"
+	"errors"
+	"testing"
+
+	"github.com/jsandas/etls"
+)
+
+func TestCRIMEFakeServer(t *testing.T) {
+	srv := &fakeFeatureServer{compression: compressionDeflate}
+	host, port := srv.start(t)
+
+	var v CRIME
+	if err := v.Check(host, port, DefaultOptions()); err != nil {
+		t.Fatal(err)
+	}
+	if v.Vulnerable != vulnerable || v.Protocol != "TLSv1.2" {
+		t.Errorf("wrong result, got: %+v", v)
+	}
+}
+
+func TestCRIMEGoServer(t *testing.T) {
+	host, port := startFeatureServer(t, &tls.Config{
+		Certificates: []tls.Certificate{featureCertificate(t, false)},
+	})
+
+	var v CRIME
+	if err := v.Check(host, port, DefaultOptions()); err != nil {
+		t.Fatal(err)
+	}
+	if v.Vulnerable != notVulnerable {
+		t.Errorf("wrong result, got: %+v", v)
+	}
+}
+
+func TestCRIMETLS13Only(t *testing.T) {
+	host, port := startFeatureServer(t, &tls.Config{
+		Certificates: []tls.Certificate{featureCertificate(t, false)},
+		MinVersion:   uint16(etls.VersionTLS13),
+	})
+
+	var v CRIME
+	v.Check(host, port, DefaultOptions())
+	if v.Vulnerable != notApplicable {
+		t.Errorf("wrong result, got: %s, want: %s", v.Vulnerable, notApplicable)
+	}
+}
+
+func TestBREACH(t *testing.T) {
+	tests := []struct {
+		https    bool
+		encoding string
+		err      error
+		want     string
+	}{
+		{true, "gzip", nil, vulnerable},
+		{true, "", nil, notVulnerable},
+		{true, "", errors.New("timeout"), testFailed},
+		{false, "", nil, notApplicable},
+	}
+
+	for _, tt := range tests {
+		var v BREACH
+		v.Check(tt.https, tt.encoding, tt.err)
+		if v.Vulnerable != tt.want || v.Encoding != tt.encoding {
+			t.Errorf("wrong result for %+v, got: %+v", tt, v)
+		}
+	}
+}
@@ -2,7 +2,7 @@ package ssl
 
 import (
 	"crypto/tls"
-	"net"
+	"errors"
 	"time"
 
 	"github.com/jsandas/etls"
@@ -23,22 +23,37 @@ func cipherStrList(uList []uint16) []string {
 
 // ConnState returns list of x509 certificates
 func ConnState(host string, port string) (connState tls.ConnectionState, tlsv int) {
+	return ConnStateWithOptions(host, port, DefaultOptions())
+}
+
+// ConnStateWithOptions returns list of x509 certificates using the
+// StartTLS options of opts
+func ConnStateWithOptions(host string, port string, opts Options) (connState tls.ConnectionState, tlsv int) {
 	var server = host + ":" + port
 
 	tlsCfg := tls.Config{
 		ServerName:         host,
 		InsecureSkipVerify: true,
+		NextProtos:         opts.StartTLS.alpn(),
 	}
 
-	conn, err := net.DialTimeout("tcp", server, 3*time.Second)
+	conn, err := opts.dial(host, port, 3*time.Second)
 	if err != nil {
 		logger.Debugf("event_id=tcp_dial_failed server=%s msg\"%v\"", server, err)
 		return
 	}
 	defer conn.Close()
 
-	err = StartTLS(conn, port)
+	err = StartTLSWithOptions(conn, host, port, opts.StartTLS)
 	if err != nil {
+		// servers behind a tls terminating proxy only accept direct tls,
+		// the negotiated postgresql ALPN protocol tells the caller to set
+		// StartTLSOptions.PostgresDirectTLS
+		if errors.Is(err, errPostgresDirectTLSOnly) && !opts.StartTLS.PostgresDirectTLS {
+			conn.Close()
+			opts.StartTLS.PostgresDirectTLS = true
+			return ConnStateWithOptions(host, port, opts)
+		}
 		return
 	}
 
@@ -54,7 +69,15 @@ func ConnState(host string, port string) (connState tls.ConnectionState, tlsv in
 }
 
 // serverDial returns boolean if destination host support specified proto/cipher combo
-func serverDial(host string, port string, proto int, ciphers []uint16) (connected bool) {
+func serverDial(host string, port string, proto int, ciphers []uint16, opts Options) (connected bool) {
+	_, connected = serverDialCipher(host, port, proto, ciphers, opts)
+
+	return
+}
+
+// serverDialCipher offers the specified proto/ciphers and returns the
+// cipher selected by the destination host
+func serverDialCipher(host string, port string, proto int, ciphers []uint16, opts Options) (cipher uint16, connected bool) {
 	var server = host + ":" + port
 
 	tlsCfg := etls.Config{
@@ -63,6 +86,7 @@ func serverDial(host string, port string, proto int, ciphers []uint16) (connecte
 		CipherSuites:       ciphers,
 		MinVersion:         uint16(proto),
 		MaxVersion:         uint16(proto),
+		NextProtos:         opts.StartTLS.alpn(),
 	}
 
 	if ciphers == nil {
@@ -71,17 +95,18 @@ func serverDial(host string, port string, proto int, ciphers []uint16) (connecte
 			InsecureSkipVerify: true,
 			MinVersion:         uint16(proto),
 			MaxVersion:         uint16(proto),
+			NextProtos:         opts.StartTLS.alpn(),
 		}
 	}
 
-	conn, err := net.DialTimeout("tcp", server, 3*time.Second)
+	conn, err := opts.dial(host, port, 3*time.Second)
 	if err != nil {
 		logger.Debugf("event_id=tcp_dial_failed server=%s msg\"%v\"", server, err)
 		return
 	}
 	defer conn.Close()
 
-	err = StartTLS(conn, port)
+	err = StartTLSWithOptions(conn, host, port, opts.StartTLS)
 	if err != nil {
 		return
 	}
@@ -92,8 +117,8 @@ func serverDial(host string, port string, proto int, ciphers []uint16) (connecte
 	if err != nil {
 		cList := cipherStrList(ciphers)
 		logger.Debugf("event_id=tls_dial_failed proto=%s cipher=%v msg\"%v\"", protocolVersionMap[proto], cList, err)
-		return false
+		return 0, false
 	}
 
-	return true
+	return client.ConnectionState().CipherSuite, true
 }
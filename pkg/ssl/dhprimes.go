@@ -0,0 +1,195 @@
+package ssl
+
+import (
+	"math/big"
+	"sync"
+)
+
+/*
+	The MODP groups (RFC 2409, RFC 3526) and the FFDHE groups (RFC 7919)
+	are generated from the binary expansion of pi and e respectively:
+
+		p = 2^n - 2^(n-64) - 1 + 2^64 * (floor(2^(n-130) * c) + k)
+
+	Rather than embedding several kilobytes of hex the primes are built
+	once from the published k values.
+*/
+
+type primeSpec struct {
+	name string
+	bits int
+	k    int64
+}
+
+var modpSpecs = []primeSpec{
+	{"rfc2409-768", 768, 149686},
+	{"rfc2409-1024", 1024, 129093},
+	{"rfc3526-1536", 1536, 741804},
+	{"rfc3526-2048", 2048, 124476},
+	{"rfc3526-3072", 3072, 1690314},
+	{"rfc3526-4096", 4096, 240904},
+	{"rfc3526-6144", 6144, 929484},
+	{"rfc3526-8192", 8192, 4743158},
+}
+
+var ffdheSpecs = []primeSpec{
+	{"ffdhe2048", 2048, 560316},
+	{"ffdhe3072", 3072, 2625351},
+	{"ffdhe4096", 4096, 5736041},
+	{"ffdhe6144", 6144, 15705020},
+	{"ffdhe8192", 8192, 10965728},
+}
+
+// publishedPrimes are common primes without a generating formula: the
+// primes hard-coded in mod_ssl (Apache 2.0/2.2) and the RFC 5114 groups
+var publishedPrimes = []struct {
+	name string
+	hex  string
+}{
+	{
+		name: "apache-512",
+		hex: "d4bcd52406f69b35994b88de5db89682c8157f62d8f33633ee5772f11f05ab22" +
+			"d6b5145b9f241e5acc31ff090a4bc71148976f76795094e71e7903529f5a824b",
+	},
+	{
+		name: "apache-1024",
+		hex: "e6969d3d495be32c7cf180c3bdd4798e91b7818251bb055e2a2064904a79a770" +
+			"fa15a259cbd523a6a6ef09c43048d5a22f971f3c20129b48000e6edd061cbc05" +
+			"3e371d794e5327df611ebbbe1bac9b5c6044cf023d76e05eea9bad991b13a63c" +
+			"974e9ef1839eb5db125136f7262e56a8871538dfd823c6505085e21f0dd5c86b",
+	},
+	{
+		name: "rfc5114-1024-160",
+		hex: "b10b8f96a080e01dde92de5eae5d54ec52c99fbcfb06a3c69a6a9dca52d23b61" +
+			"6073e28675a23d189838ef1e2ee652c013ecb4aea906112324975c3cd49b83bf" +
+			"accbdd7d90c4bd7098488e9c219a73724effd6fae5644738faa31a4ff55bccc0" +
+			"a151af5f0dc8b4bd45bf37df365c1a65e68cfda76d4da708df1fb2bc2e4a4371",
+	},
+	{
+		name: "rfc5114-2048-224",
+		hex: "ad107e1e9123a9d0d660faa79559c51fa20d64e5683b9fd1b54b1597b61d0a75" +
+			"e6fa141df95a56dbaf9a3c407ba1df15eb3d688a309c180e1de6b85a1274a0a6" +
+			"6d3f8152ad6ac2129037c9edefda4df8d91e8fef55b7394b7ad5b7d0b6c12207" +
+			"c9f98d11ed34dbf6c6ba0b2c8bbc27be6a00e0a0b9c49708b3bf8a3170918836" +
+			"81286130bc8985db1602e714415d9330278273c7de31efdc7310f7121fd5a074" +
+			"15987d9adc0a486dcdf93acc44328387315d75e198c641a480cd86a1b9e587e8" +
+			"be60e69cc928b2b9c52172e413042e9b23f10b0e16e79763c9b53dcf4ba80a29" +
+			"e3fb73c16b8e75b97ef363e2ffa31f71cf9de5384e71b81c0ac4dffe0c10e64f",
+	},
+	{
+		name: "rfc5114-2048-256",
+		hex: "87a8e61db4b6663cffbbd19c651959998ceef608660dd0f25d2ceed4435e3b00" +
+			"e00df8f1d61957d4faf7df4561b2aa3016c3d91134096faa3bf4296d830e9a7c" +
+			"209e0c6497517abd5a8a9d306bcf67ed91f9e6725b4758c022e0b1ef4275bf7b" +
+			"6c5bfc11d45f9088b941f54eb1e59bb8bc39a0bf12307f5c4fdb70c581b23f76" +
+			"b63acae1caa6b7902d52526735488a0ef13c6d9a51bfa4ab3ad8347796524d8e" +
+			"f6a167b5a41825d967e144e5140564251ccacb83e6b486f6b3ca3f7971506026" +
+			"c0b857f689962856ded4010abd0be621c3a3960a54e710c375f26375d7014103" +
+			"a4b54330c198af126116d2276e11715f693877fad7ef09cadb094ae91e1a1597",
+	},
+}
+
+// constPrecision is the number of fractional bits computed for pi and e
+const constPrecision = 8192 + 64
+
+var (
+	primesOnce sync.Once
+	// wellKnownPrimes maps the hex encoding of a prime to its name
+	wellKnownPrimes map[string]string
+)
+
+// primeName returns the name of a well-known prime or an empty string
+func primeName(p *big.Int) string {
+	primesOnce.Do(buildPrimes)
+	return wellKnownPrimes[p.Text(16)]
+}
+
+// wellKnownPrime returns the prime with the given name
+func wellKnownPrime(name string) *big.Int {
+	primesOnce.Do(buildPrimes)
+	for h, n := range wellKnownPrimes {
+		if n == name {
+			p, _ := new(big.Int).SetString(h, 16)
+			return p
+		}
+	}
+	return nil
+}
+
+func buildPrimes() {
+	wellKnownPrimes = make(map[string]string)
+
+	pi := scaledPi(constPrecision)
+	e := scaledE(constPrecision)
+
+	for _, s := range modpSpecs {
+		wellKnownPrimes[generatePrime(s, pi).Text(16)] = s.name
+	}
+	for _, s := range ffdheSpecs {
+		wellKnownPrimes[generatePrime(s, e).Text(16)] = s.name
+	}
+	for _, s := range publishedPrimes {
+		wellKnownPrimes[s.hex] = s.name
+	}
+}
+
+// generatePrime builds the prime described by s from constant c, which is
+// scaled by 2^constPrecision
+func generatePrime(s primeSpec, c *big.Int) *big.Int {
+	one := big.NewInt(1)
+
+	p := new(big.Int).Lsh(one, uint(s.bits))
+	p.Sub(p, new(big.Int).Lsh(one, uint(s.bits-64)))
+	p.Sub(p, one)
+
+	m := new(big.Int).Rsh(c, uint(constPrecision-(s.bits-130)))
+	m.Add(m, big.NewInt(s.k))
+	m.Lsh(m, 64)
+
+	return p.Add(p, m)
+}
+
+// scaledPi returns pi * 2^bits using Machin's formula
+func scaledPi(bits uint) *big.Int {
+	one := new(big.Int).Lsh(big.NewInt(1), bits)
+
+	pi := arctanInv(5, one)
+	pi.Mul(pi, big.NewInt(4))
+	pi.Sub(pi, arctanInv(239, one))
+
+	return pi.Mul(pi, big.NewInt(4))
+}
+
+// arctanInv returns arctan(1/x) scaled by one
+func arctanInv(x int64, one *big.Int) *big.Int {
+	bx := big.NewInt(x)
+	x2 := big.NewInt(x * x)
+
+	term := new(big.Int).Quo(one, bx)
+	sum := new(big.Int).Set(term)
+
+	for n, sign := int64(3), -1; term.Sign() != 0; n, sign = n+2, -sign {
+		term.Quo(term, x2)
+		t := new(big.Int).Quo(term, big.NewInt(n))
+		if sign < 0 {
+			sum.Sub(sum, t)
+		} else {
+			sum.Add(sum, t)
+		}
+	}
+
+	return sum
+}
+
+// scaledE returns e * 2^bits
+func scaledE(bits uint) *big.Int {
+	term := new(big.Int).Lsh(big.NewInt(1), bits)
+	sum := new(big.Int)
+
+	for k := int64(1); term.Sign() != 0; k++ {
+		sum.Add(sum, term)
+		term.Quo(term, big.NewInt(k))
+	}
+
+	return sum
+}
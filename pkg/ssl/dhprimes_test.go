@@ -0,0 +1,63 @@
+package ssl
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestWellKnownPrimes(t *testing.T) {
+	specs := append(append([]primeSpec{}, modpSpecs...), ffdheSpecs...)
+
+	for _, s := range specs {
+		p := wellKnownPrime(s.name)
+		if p == nil {
+			t.Fatalf("prime not generated: %s", s.name)
+		}
+
+		if p.BitLen() != s.bits {
+			t.Errorf("wrong size for %s, got: %d, want: %d", s.name, p.BitLen(), s.bits)
+		}
+
+		if !p.ProbablyPrime(0) {
+			t.Errorf("%s is not prime", s.name)
+		}
+	}
+}
+
+func TestPublishedPrimes(t *testing.T) {
+	for _, s := range publishedPrimes {
+		p, ok := new(big.Int).SetString(s.hex, 16)
+		if !ok || !p.ProbablyPrime(20) {
+			t.Errorf("%s is not prime", s.name)
+			continue
+		}
+
+		if n := primeName(p); n != s.name {
+			t.Errorf("wrong prime name, got: %s, want: %s", n, s.name)
+		}
+	}
+}
+
+func TestPrimeName(t *testing.T) {
+	// leading digits published in RFC 3526 and RFC 7919
+	tests := map[string]string{
+		"rfc3526-2048": "ffffffffffffffffc90fdaa22168c234c4c6628b80dc1cd1",
+		"ffdhe2048":    "ffffffffffffffffadf85458a2bb4a9aafdc5620273d3cf1",
+	}
+
+	for name, prefix := range tests {
+		p := wellKnownPrime(name)
+		if !strings.HasPrefix(p.Text(16), prefix) {
+			t.Errorf("wrong digits for %s, got: %s", name, p.Text(16)[:len(prefix)])
+		}
+
+		if n := primeName(p); n != name {
+			t.Errorf("wrong prime name, got: %s, want: %s", n, name)
+		}
+	}
+
+	if n := primeName(big.NewInt(23)); n != "" {
+		t.Errorf("unknown prime should not have a name, got: %s", n)
+	}
+}
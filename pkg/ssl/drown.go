@@ -0,0 +1,70 @@
+package ssl
+
+import (
+	"crypto/x509"
+	"strings"
+
+	logger "github.com/jsandas/gologger"
+	"github.com/jsandas/tlstools/pkg/certutil"
+)
+
+// DROWN checks if SSLv2 can be used to decrypt TLS sessions with the
+// server's RSA key (CVE-2016-0800)
+type DROWN struct {
+	Vulnerable string `json:"vulnerable"`
+	// SSLv2 is true when the scanned service supports SSLv2
+	SSLv2 bool `json:"sslv2"`
+	// ExportCiphers are the SSLv2 export ciphers offered by the service
+	ExportCiphers []string `json:"exportCiphers,omitempty"`
+	// SharedKey lists the other ports presenting the same RSA key with
+	// SSLv2 enabled
+	SharedKey []string `json:"sharedKey,omitempty"`
+}
+
+// Check derives the verdict from the SSLv2 ciphers of the scanned service
+func (v *DROWN) Check(supported map[string][]CipherResult) {
+	v.ExportCiphers = nil
+	ciphers, ok := supported["SSLv2"]
+	v.SSLv2 = ok && len(ciphers) > 0
+
+	for _, c := range ciphers {
+		if strings.Contains(c.Name, "EXPORT") {
+			v.ExportCiphers = append(v.ExportCiphers, c.Name)
+		}
+	}
+
+	v.Vulnerable = notVulnerable
+	if v.SSLv2 || len(v.SharedKey) > 0 {
+		v.Vulnerable = vulnerable
+	}
+
+	logger.Debugf("event_id=drown sslv2=%v export_ciphers=%v", v.SSLv2, v.ExportCiphers)
+}
+
+// CrossCheck connects to the other ports of host with SSLv2 and flags
+// those presenting the RSA key with the given public key fingerprint
+// (see certutil.PublicKeyFingerprint)
+func (v *DROWN) CrossCheck(host string, ports []string, fingerprint string, opts Options) {
+	v.SharedKey = nil
+	for _, port := range ports {
+		hello := sslv2ServerHello(host, port, opts)
+		if hello == nil || len(sslv2GetCiphers(hello)) == 0 {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(sslv2GetCertificate(hello))
+		if err != nil {
+			logger.Debugf("event_id=drown_certificate_invalid port=%s msg=\"%v\"", port, err)
+			continue
+		}
+
+		if cert.PublicKeyAlgorithm == x509.RSA && certutil.PublicKeyFingerprint(cert) == fingerprint {
+			logger.Debugf("event_id=drown_shared_key port=%s", port)
+			v.SharedKey = append(v.SharedKey, port)
+		}
+	}
+
+	if len(v.SharedKey) > 0 {
+		v.Vulnerable = vulnerable
+	}
+}
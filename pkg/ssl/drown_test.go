@@ -0,0 +1,98 @@
+package ssl
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/jsandas/tlstools/pkg/certutil"
+)
+
+// startSSLv2Server answers every connection with an SSLv2 ServerHello
+// carrying cert and the given cipher kinds
+func startSSLv2Server(t *testing.T, cert []byte, kinds ...string) string {
+	var specs []byte
+	for _, k := range kinds {
+		b, _ := hex.DecodeString(k)
+		specs = append(specs, b...)
+	}
+
+	body := []byte{4, 0, 1, 0, 2}
+	body = append(body, uint16Bytes(uint16(len(cert)))...)
+	body = append(body, uint16Bytes(uint16(len(specs)))...)
+	body = append(body, 0, 16)
+	body = append(body, cert...)
+	body = append(body, specs...)
+	body = append(body, make([]byte, 16)...)
+	hello := append(uint16Bytes(uint16(0x8000|len(body))), body...)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Read(make([]byte, 1024))
+			conn.Write(hello)
+			conn.Close()
+		}
+	}()
+
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	return port
+}
+
+func TestDROWN(t *testing.T) {
+	var v DROWN
+
+	v.Check(map[string][]CipherResult{
+		"SSLv2":   {newCipherResult("SSL2_RC4_128_WITH_MD5"), newCipherResult("SSL2_RC4_128_EXPORT40_WITH_MD5")},
+		"TLSv1.2": {newCipherResult("TLS_RSA_WITH_AES_128_CBC_SHA")},
+	})
+	if v.Vulnerable != vulnerable || !v.SSLv2 || !reflect.DeepEqual(v.ExportCiphers, []string{"SSL2_RC4_128_EXPORT40_WITH_MD5"}) {
+		t.Errorf("wrong result, got: %+v", v)
+	}
+
+	v = DROWN{}
+	v.Check(map[string][]CipherResult{"TLSv1.2": {newCipherResult("TLS_RSA_WITH_AES_128_CBC_SHA")}})
+	if v.Vulnerable != notVulnerable || v.SSLv2 {
+		t.Errorf("wrong result, got: %+v", v)
+	}
+}
+
+func TestDROWNCrossCheck(t *testing.T) {
+	cert := rsaCertificate(t, testRSAKey(t))
+	parsed, _ := x509.ParseCertificate(cert.Certificate[0])
+
+	shared := startSSLv2Server(t, cert.Certificate[0], "010080", "020080")
+	other := startSSLv2Server(t, featureCertificate(t, false).Certificate[0], "010080")
+	_, tlsOnly := startFeatureServer(t, &tls.Config{Certificates: []tls.Certificate{cert}})
+
+	var v DROWN
+	v.Check(map[string][]CipherResult{"TLSv1.2": {newCipherResult("TLS_RSA_WITH_AES_128_CBC_SHA")}})
+	v.CrossCheck("127.0.0.1", []string{shared, other, tlsOnly}, certutil.PublicKeyFingerprint(parsed), DefaultOptions())
+
+	if v.Vulnerable != vulnerable || v.SSLv2 || !reflect.DeepEqual(v.SharedKey, []string{shared}) {
+		t.Errorf("wrong result, got: %+v", v)
+	}
+}
+
+func TestSSLv2GetCertificate(t *testing.T) {
+	b, _ := hex.DecodeString("83bf0400010002000300010010aabbcc")
+	if got := sslv2GetCertificate(b); hex.EncodeToString(got) != "aabbcc" {
+		t.Errorf("wrong certificate, got: %x", got)
+	}
+
+	if got := sslv2GetCertificate(b[:14]); got != nil {
+		t.Errorf("truncated hello should not return a certificate, got: %x", got)
+	}
+}
@@ -0,0 +1,74 @@
+package ssl
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/jsandas/etls"
+	logger "github.com/jsandas/gologger"
+)
+
+// vulnerability check results
+const (
+	notVulnerable = "no"
+	vulnerable    = "yes"
+	testFailed    = "error"
+)
+
+// alertInappropriateFallback is sent by servers enforcing TLS_FALLBACK_SCSV
+const alertInappropriateFallback = 86
+
+// FallbackSCSV checks if the server rejects a downgraded handshake that
+// carries TLS_FALLBACK_SCSV (RFC 7507)
+type FallbackSCSV struct {
+	Vulnerable string `json:"vulnerable"`
+	// Protocol is the downgraded protocol offered with the SCSV
+	Protocol string `json:"protocol,omitempty"`
+}
+
+// Check offers the second highest of the supported protocols found by
+// Check with TLS_FALLBACK_SCSV
+func (f *FallbackSCSV) Check(host string, port string, supported map[string][]string, opts Options) error {
+	var protocols []int
+	for name := range supported {
+		if p, ok := protocolVersion(name); ok {
+			protocols = append(protocols, p)
+		}
+	}
+	slices.Sort(protocols)
+
+	return f.check(host, port, protocols, opts)
+}
+
+func (f *FallbackSCSV) check(host string, port string, protocols []int, opts Options) error {
+	if len(protocols) < 2 {
+		f.Vulnerable = notApplicable
+		return nil
+	}
+
+	p := protocols[len(protocols)-2]
+	f.Protocol = protocolVersionMap[p]
+
+	ciphers := append(protocolCiphers(p), etls.TLS_FALLBACK_SCSV)
+	hello := newClientHello(host, p, ciphers)
+	if p > etls.VersionSSL30 {
+		hello.setGroups(kexGroups(p))
+	}
+
+	flight, err := rawHandshake(host, port, hello, opts)
+
+	var alert alertError
+	if errors.As(err, &alert) && alert.description == alertInappropriateFallback {
+		f.Vulnerable = notVulnerable
+		return nil
+	}
+
+	if flight != nil && flight.hello != nil {
+		logger.Debugf("event_id=fallback_scsv_accepted proto=%s", f.Protocol)
+		f.Vulnerable = vulnerable
+		return nil
+	}
+
+	f.Vulnerable = testFailed
+	return err
+}
@@ -0,0 +1,50 @@
+package ssl
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/jsandas/etls"
+)
+
+func TestFallbackSCSV(t *testing.T) {
+	host, port := startFeatureServer(t, &tls.Config{
+		Certificates: []tls.Certificate{featureCertificate(t, false)},
+		MinVersion:   uint16(etls.VersionTLS12),
+		MaxVersion:   uint16(etls.VersionTLS13),
+	})
+
+	supported := map[string][]string{"TLSv1.2": {}, "TLSv1.3": {}, "SSLv2": {}}
+
+	var f FallbackSCSV
+	if err := f.Check(host, port, supported, DefaultOptions()); err != nil {
+		t.Fatal(err)
+	}
+
+	if f.Vulnerable != notVulnerable || f.Protocol != "TLSv1.2" {
+		t.Errorf("wrong fallback result, got: %+v", f)
+	}
+}
+
+func TestFallbackSCSVIgnored(t *testing.T) {
+	srv := &fakeFeatureServer{}
+	host, port := srv.start(t)
+
+	var f FallbackSCSV
+	if err := f.check(host, port, []int{etls.VersionTLS11, etls.VersionTLS12}, DefaultOptions()); err != nil {
+		t.Fatal(err)
+	}
+
+	if f.Vulnerable != vulnerable || f.Protocol != "TLSv1.1" {
+		t.Errorf("wrong fallback result, got: %+v", f)
+	}
+}
+
+func TestFallbackSCSVSingleProtocol(t *testing.T) {
+	var f FallbackSCSV
+	f.check("127.0.0.1", "1", []int{etls.VersionTLS13}, DefaultOptions())
+
+	if f.Vulnerable != notApplicable {
+		t.Errorf("wrong fallback result, got: %+v", f)
+	}
+}
@@ -0,0 +1,427 @@
+package ssl
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/binary"
+	"net"
+	"slices"
+	"time"
+
+	"github.com/jsandas/etls"
+	logger "github.com/jsandas/gologger"
+)
+
+// alpnProtocols are the application protocols tested by CheckFeatures
+var alpnProtocols = []string{"h2", "http/1.1"}
+
+// oidTLSFeature is the TLS feature certificate extension (RFC 7633)
+var oidTLSFeature = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 1, 24}
+
+// Features are the TLS extensions and handshake features supported by
+// the server. The extension checks are performed with the highest
+// protocol below TLS 1.3 as the extensions are encrypted in TLS 1.3
+type Features struct {
+	SecureRenegotiation  bool `json:"secureRenegotiation"`
+	ExtendedMasterSecret bool `json:"extendedMasterSecret"`
+	EncryptThenMAC       bool `json:"encryptThenMac"`
+	SessionTickets       bool `json:"sessionTickets"`
+	// SessionTicketLifetime is the ticket lifetime hint in seconds
+	SessionTicketLifetime uint32   `json:"sessionTicketLifetime"`
+	SessionIDResumption   bool     `json:"sessionIdResumption"`
+	ALPN                  []string `json:"alpn"`
+	NPN                   []string `json:"npn"`
+	OCSPMustStaple        bool     `json:"ocspMustStaple"`
+	// SCTExtension is true when SCTs are delivered in the TLS extension
+	SCTExtension bool `json:"sctExtension"`
+	Heartbeat    bool `json:"heartbeat"`
+	// PostgresDirectTLS is true when a Postgres server accepts TLS without
+	// SSLRequest (Postgres 17+)
+	PostgresDirectTLS bool `json:"postgresDirectTls,omitempty"`
+	// XMPPTLSRequired is set for XMPP servers, true when STARTTLS is
+	// marked as required in the stream features
+	XMPPTLSRequired *bool `json:"xmppTlsRequired,omitempty"`
+	// RDPSecurity is the security protocol negotiated by an RDP server
+	// (standard, tls or credssp)
+	RDPSecurity string `json:"rdpSecurity,omitempty"`
+}
+
+// CheckFeatures detects the TLS extensions and features of the server
+func CheckFeatures(host string, port string, supported map[string][]string, opts Options) Features {
+	var f Features
+
+	p := legacyProtocol(supported)
+	ciphers := cipherIDList(supported[protocolVersionMap[p]])
+
+	jobs := []func(){
+		func() { f.ALPN = alpnSupported(host, port, p, ciphers, opts) },
+		func() {
+			state, _ := ConnStateWithOptions(host, port, opts)
+			if len(state.PeerCertificates) > 0 {
+				f.OCSPMustStaple = mustStaple(state.PeerCertificates[0])
+			}
+			f.SCTExtension = len(state.SignedCertificateTimestamps) > 0
+		},
+	}
+
+	switch opts.StartTLS.ServiceFor(port) {
+	case "postgres":
+		jobs = append(jobs, func() { f.PostgresDirectTLS = postgresDirectTLS(host, port, p, ciphers, opts) })
+	case "xmpp", "xmpp-server":
+		jobs = append(jobs, func() {
+			if required, err := xmppTLSRequired(host, port, opts); err == nil {
+				f.XMPPTLSRequired = &required
+			}
+		})
+	case "rdp":
+		jobs = append(jobs, func() { f.RDPSecurity, _ = RDPSecurity(host, port, opts) })
+	}
+
+	if p != 0 && len(ciphers) > 0 {
+		jobs = append(jobs,
+			func() {
+				exts := serverExtensions(host, port, p, ciphers, opts, extRenegotiationInfo, extExtendedMasterSecret, extHeartbeat)
+				_, f.SecureRenegotiation = exts[extRenegotiationInfo]
+				_, f.ExtendedMasterSecret = exts[extExtendedMasterSecret]
+				_, f.Heartbeat = exts[extHeartbeat]
+			},
+			func() {
+				exts := serverExtensions(host, port, p, blockCiphers(ciphers), opts, extEncryptThenMAC)
+				_, f.EncryptThenMAC = exts[extEncryptThenMAC]
+			},
+			func() {
+				exts := serverExtensions(host, port, p, ciphers, opts, extNextProtoNeg)
+				f.NPN = parseNPN(exts[extNextProtoNeg])
+			},
+			func() { f.SessionTickets, f.SessionTicketLifetime = sessionTickets(host, port, p, ciphers, opts) },
+			func() { f.SessionIDResumption = sessionIDResumption(host, port, p, ciphers, opts) },
+		)
+	}
+
+	runPool(opts.workers(), jobs)
+
+	logger.Debugf("event_id=tls_features results=%+v", f)
+	return f
+}
+
+// legacyProtocol returns the highest supported protocol from TLS 1.0 to
+// TLS 1.2 or 0 if there is none
+func legacyProtocol(supported map[string][]string) int {
+	for _, p := range []int{etls.VersionTLS12, etls.VersionTLS11, etls.VersionTLS10} {
+		if _, ok := supported[protocolVersionMap[p]]; ok {
+			return p
+		}
+	}
+	return 0
+}
+
+// blockCiphers returns the ciphers that use a block cipher with HMAC
+func blockCiphers(ciphers []uint16) []uint16 {
+	var block []uint16
+	for _, id := range ciphers {
+		if isCBC(cipherSuites[id]) {
+			block = append(block, id)
+		}
+	}
+	return block
+}
+
+// serverExtensions offers the extensions in a ClientHello and returns the
+// extensions of the ServerHello
+func serverExtensions(host string, port string, p int, ciphers []uint16, opts Options, exts ...uint16) map[uint16][]byte {
+	if len(ciphers) == 0 {
+		return nil
+	}
+
+	hello := newClientHello(host, p, ciphers)
+	hello.setGroups(kexGroups(p))
+	for _, e := range exts {
+		switch e {
+		case extRenegotiationInfo:
+			hello.setExtension(e, []byte{0})
+		case extHeartbeat:
+			// peer_allowed_to_send
+			hello.setExtension(e, []byte{1})
+		default:
+			hello.setExtension(e, []byte{})
+		}
+	}
+
+	flight, err := rawHandshake(host, port, hello, opts)
+	if flight == nil || flight.hello == nil {
+		logger.Debugf("event_id=extension_probe_failed exts=%v msg=\"%v\"", exts, err)
+		return nil
+	}
+
+	return flight.hello.extensions
+}
+
+// kexGroups returns the curves offered for protocol p
+func kexGroups(p int) []uint16 {
+	var groups []uint16
+	for g := range curveSizes {
+		if groupApplies(g, p) {
+			groups = append(groups, g)
+		}
+	}
+	slices.Sort(groups)
+	return groups
+}
+
+// parseNPN parses the protocol list of a next_protocol_negotiation extension
+func parseNPN(b []byte) []string {
+	var protos []string
+	for len(b) > 0 {
+		l := int(b[0])
+		if len(b) < 1+l {
+			break
+		}
+		protos = append(protos, string(b[1:1+l]))
+		b = b[1+l:]
+	}
+	return protos
+}
+
+// alpnSupported offers each of alpnProtocols on its own and returns the
+// ones selected by the server. The selection is read from the ServerHello
+// of protocol p offering the supported ciphers, servers without a protocol
+// below TLS 1.3 are tested with a TLS 1.3 handshake
+func alpnSupported(host string, port string, p int, ciphers []uint16, opts Options) []string {
+	var protos []string
+
+	for _, proto := range alpnProtocols {
+		var selected string
+
+		if p != 0 && len(ciphers) > 0 {
+			hello := newClientHello(host, p, ciphers)
+			hello.setGroups(kexGroups(p))
+			hello.setExtension(extALPN, alpnData([]string{proto}))
+
+			if flight, _ := rawHandshake(host, port, hello, opts); flight != nil && flight.hello != nil {
+				selected = parseALPN(flight.hello.extensions[extALPN])
+			}
+		} else {
+			cfg := &tls.Config{
+				ServerName:         host,
+				InsecureSkipVerify: true,
+				MinVersion:         uint16(etls.VersionTLS13),
+				NextProtos:         []string{proto},
+			}
+
+			if state, _, err := recordedHandshake(host, port, cfg, opts); err == nil {
+				selected = state.NegotiatedProtocol
+			}
+		}
+
+		if selected == proto {
+			protos = append(protos, proto)
+		}
+	}
+
+	return protos
+}
+
+// parseALPN returns the protocol selected in an ALPN extension
+func parseALPN(b []byte) string {
+	if len(b) < 3 || len(b) < 3+int(b[2]) {
+		return ""
+	}
+	return string(b[3 : 3+int(b[2])])
+}
+
+// probeConfig returns a crypto/tls config for protocol p offering ciphers.
+// The default suites of crypto/tls lack RSA key exchange and 3DES, servers
+// only supporting those would refuse a handshake without them
+func probeConfig(host string, p int, ciphers []uint16) *tls.Config {
+	return &tls.Config{
+		ServerName:         host,
+		InsecureSkipVerify: true,
+		MinVersion:         uint16(p),
+		MaxVersion:         uint16(p),
+		CipherSuites:       ciphers,
+	}
+}
+
+// mustStaple reports if the certificate has the TLS feature extension
+// requiring status_request
+func mustStaple(cert *x509.Certificate) bool {
+	for _, ext := range cert.Extensions {
+		if !ext.Id.Equal(oidTLSFeature) {
+			continue
+		}
+		var features []int
+		if _, err := asn1.Unmarshal(ext.Value, &features); err != nil {
+			return false
+		}
+		// status_request
+		return slices.Contains(features, 5)
+	}
+	return false
+}
+
+// sessionTickets performs a full handshake offering a session ticket and
+// returns if a ticket was issued and its lifetime hint
+func sessionTickets(host string, port string, p int, ciphers []uint16, opts Options) (bool, uint32) {
+	lifetime, ticket := newSessionTicket(host, port, p, ciphers, opts)
+	return ticket != nil, lifetime
+}
+
+// newSessionTicket performs a full handshake offering ciphers and returns
+// the lifetime hint and ticket of the server's NewSessionTicket message.
+// Only the ciphers implemented by crypto/tls can complete the handshake
+func newSessionTicket(host string, port string, p int, ciphers []uint16, opts Options) (uint32, []byte) {
+	cfg := probeConfig(host, p, ciphers)
+	cfg.ClientSessionCache = tls.NewLRUClientSessionCache(1)
+
+	_, recorded, err := recordedHandshake(host, port, cfg, opts)
+	if err != nil {
+		return 0, nil
+	}
+
+	for _, msg := range clearHandshakeMessages(recorded) {
+		if msg[0] != typeNewSessionTicket || len(msg) < 7 {
+			continue
+		}
+		l := int(binary.BigEndian.Uint16(msg[5:7]))
+		if len(msg) < 7+l {
+			break
+		}
+		return binary.BigEndian.Uint32(msg[1:5]), msg[7 : 7+l]
+	}
+
+	return 0, nil
+}
+
+// sessionIDResumption performs a full handshake and then offers the
+// session id assigned by the server. The session is resumed when the
+// server echoes the id and continues with ChangeCipherSpec
+func sessionIDResumption(host string, port string, p int, ciphers []uint16, opts Options) bool {
+	cfg := probeConfig(host, p, ciphers)
+	cfg.SessionTicketsDisabled = true
+
+	// the server hello is read even if the handshake fails
+	_, recorded, _ := recordedHandshake(host, port, cfg, opts)
+
+	var sessionID []byte
+	for _, msg := range clearHandshakeMessages(recorded) {
+		if msg[0] != typeServerHello {
+			continue
+		}
+		if sh, err := parseServerHello(msg[1:]); err == nil {
+			sessionID = sh.sessionID
+		}
+		break
+	}
+
+	if len(sessionID) == 0 {
+		return false
+	}
+
+	conn, err := rawDial(host, port, opts)
+	if err != nil {
+		return false
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	hello := newClientHello(host, p, ciphers)
+	hello.setGroups(kexGroups(p))
+	hello.sessionID = sessionID
+	hello.setStartTLS(opts.StartTLS)
+
+	if _, err := conn.Write(hello.marshal()); err != nil {
+		return false
+	}
+
+	hr := &handshakeReader{r: conn}
+	typ, body, err := hr.next()
+	if err != nil || typ != typeServerHello {
+		return false
+	}
+
+	sh, err := parseServerHello(body)
+	if err != nil || !bytes.Equal(sh.sessionID, sessionID) {
+		return false
+	}
+
+	return abbreviatedHandshake(conn, hr)
+}
+
+// abbreviatedHandshake reports if the ServerHello read by hr is directly
+// followed by ChangeCipherSpec, which means the session was resumed
+func abbreviatedHandshake(conn net.Conn, hr *handshakeReader) bool {
+	if len(hr.buf) > 0 {
+		return false
+	}
+
+	typ, _, _, err := readRecord(conn)
+	return err == nil && typ == recordTypeChangeCipherSpec
+}
+
+// recordingConn keeps a copy of everything read from the connection
+type recordingConn struct {
+	net.Conn
+	buf bytes.Buffer
+}
+
+func (c *recordingConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	c.buf.Write(b[:n])
+	return n, err
+}
+
+// recordedHandshake performs a handshake with cfg and returns the bytes
+// sent by the server
+func recordedHandshake(host string, port string, cfg *tls.Config, opts Options) (tls.ConnectionState, []byte, error) {
+	conn, err := rawDial(host, port, opts)
+	if err != nil {
+		return tls.ConnectionState{}, nil, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	if cfg.NextProtos == nil {
+		cfg = cfg.Clone()
+		cfg.NextProtos = opts.StartTLS.alpn()
+	}
+
+	rc := &recordingConn{Conn: conn}
+	client := tls.Client(rc, cfg)
+
+	err = client.Handshake()
+	if err != nil {
+		logger.Debugf("event_id=recorded_handshake_failed msg=\"%v\"", err)
+		return tls.ConnectionState{}, rc.buf.Bytes(), err
+	}
+
+	return client.ConnectionState(), rc.buf.Bytes(), nil
+}
+
+// clearHandshakeMessages returns the handshake messages sent before the
+// server's ChangeCipherSpec. Each message is its type followed by the body
+func clearHandshakeMessages(b []byte) [][]byte {
+	var msgs [][]byte
+	var buf []byte
+
+	r := bytes.NewReader(b)
+	for {
+		typ, _, fragment, err := readRecord(r)
+		if err != nil || typ != recordTypeHandshake {
+			break
+		}
+		buf = append(buf, fragment...)
+	}
+
+	for len(buf) >= 4 {
+		l := int(buf[1])<<16 | int(buf[2])<<8 | int(buf[3])
+		if len(buf) < 4+l {
+			break
+		}
+		msgs = append(msgs, append([]byte{buf[0]}, buf[4:4+l]...))
+		buf = buf[4+l:]
+	}
+
+	return msgs
+}
@@ -0,0 +1,382 @@
+package ssl
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"math/big"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jsandas/etls"
+)
+
+var featureCiphers = map[string][]string{
+	"TLSv1.2": {"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256", "TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA"},
+}
+
+// featureCertificate returns a self-signed certificate, optionally with
+// the OCSP must-staple extension
+func featureCertificate(t *testing.T, staple bool) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	if staple {
+		// SEQUENCE { INTEGER 5 }
+		tmpl.ExtraExtensions = []pkix.Extension{{Id: oidTLSFeature, Value: []byte{0x30, 0x03, 0x02, 0x01, 0x05}}}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+// startFeatureServer runs a Go TLS server with cfg
+func startFeatureServer(t *testing.T, cfg *tls.Config) (string, string) {
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				conn.(*tls.Conn).Handshake()
+				conn.Close()
+			}()
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	return host, port
+}
+
+// fakeFeatureServer answers ClientHellos with a ServerHello carrying the
+// configured extensions (when offered by the client), an empty Certificate
+// and ServerHelloDone. The first offered cipher is selected, a hello
+// offering sessionID is resumed and hellos matching reject are dropped.
+// The ServerHello has version or TLS 1.2 if unset, random or zeros if
+// unset and selects compression. A set keyExchange is sent as
+// ServerKeyExchange
+type fakeFeatureServer struct {
+	version     uint16
+	random      []byte
+	compression uint8
+	extensions  map[uint16][]byte
+	sessionID   []byte
+	keyExchange []byte
+	reject      func(hello []byte) bool
+}
+
+func (s *fakeFeatureServer) start(t *testing.T) (string, string) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.handle(conn)
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	return host, port
+}
+
+func (s *fakeFeatureServer) handle(conn net.Conn) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	hr := &handshakeReader{r: conn}
+	typ, body, err := hr.next()
+	if err != nil || typ != typeClientHello {
+		return
+	}
+
+	if s.reject != nil && s.reject(body) {
+		return
+	}
+
+	sessionID, cipher, offered := parseTestClientHello(body)
+
+	if s.sessionID != nil && bytes.Equal(sessionID, s.sessionID) {
+		conn.Write(record(recordTypeHandshake, etls.VersionTLS12, s.serverHello(sessionID, cipher, nil)))
+		conn.Write(record(recordTypeChangeCipherSpec, etls.VersionTLS12, []byte{1}))
+		return
+	}
+
+	var flight []byte
+	flight = append(flight, s.serverHello(s.sessionID, cipher, offered)...)
+	flight = append(flight, handshakeMessage(typeCertificate, []byte{0, 0, 0})...)
+	if s.keyExchange != nil {
+		flight = append(flight, handshakeMessage(typeServerKeyExchange, s.keyExchange)...)
+	}
+	flight = append(flight, handshakeMessage(typeServerHelloDone, nil)...)
+	conn.Write(record(recordTypeHandshake, etls.VersionTLS12, flight))
+
+	// wait for the client to give up
+	conn.Read(make([]byte, 1024))
+}
+
+func (s *fakeFeatureServer) serverHello(sessionID []byte, cipher uint16, offered []uint16) []byte {
+	version := s.version
+	if version == 0 {
+		version = etls.VersionTLS12
+	}
+
+	random := s.random
+	if random == nil {
+		random = make([]byte, 32)
+	}
+
+	b := uint16Bytes(version)
+	b = append(b, random...)
+	b = append(b, byte(len(sessionID)))
+	b = append(b, sessionID...)
+	b = append(b, uint16Bytes(cipher)...)
+	b = append(b, s.compression)
+
+	var exts []byte
+	for _, id := range offered {
+		data, ok := s.extensions[id]
+		if !ok {
+			continue
+		}
+		exts = append(exts, uint16Bytes(id)...)
+		exts = append(exts, uint16Bytes(uint16(len(data)))...)
+		exts = append(exts, data...)
+	}
+	b = append(b, uint16Bytes(uint16(len(exts)))...)
+	b = append(b, exts...)
+
+	return handshakeMessage(typeServerHello, b)
+}
+
+// parseTestClientHello returns the session id, first cipher and extensions
+// of a ClientHello body
+func parseTestClientHello(b []byte) (sessionID []byte, cipher uint16, exts []uint16) {
+	b = b[34:]
+	sessionID = b[1 : 1+int(b[0])]
+	b = b[1+int(b[0]):]
+
+	l := int(binary.BigEndian.Uint16(b))
+	cipher = binary.BigEndian.Uint16(b[2:4])
+	b = b[2+l:]
+	b = b[1+int(b[0]):]
+
+	if len(b) < 2 {
+		return
+	}
+	b = b[2:]
+	for len(b) >= 4 {
+		exts = append(exts, binary.BigEndian.Uint16(b))
+		b = b[4+int(binary.BigEndian.Uint16(b[2:4])):]
+	}
+
+	return
+}
+
+func TestCheckFeaturesGoServer(t *testing.T) {
+	cert := featureCertificate(t, true)
+	cert.SignedCertificateTimestamps = [][]byte{[]byte("sct")}
+
+	host, port := startFeatureServer(t, &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   uint16(etls.VersionTLS12),
+		NextProtos:   []string{"h2", "http/1.1"},
+	})
+
+	f := CheckFeatures(host, port, featureCiphers, DefaultOptions())
+
+	if !f.SecureRenegotiation {
+		t.Errorf("secure renegotiation should be supported")
+	}
+	if !f.ExtendedMasterSecret {
+		t.Errorf("extended master secret should be supported")
+	}
+	if f.EncryptThenMAC {
+		t.Errorf("encrypt-then-mac should not be supported")
+	}
+	if !f.SessionTickets {
+		t.Errorf("session tickets should be supported")
+	}
+	if f.SessionIDResumption {
+		t.Errorf("session id resumption should not be supported")
+	}
+	if !reflect.DeepEqual(f.ALPN, []string{"h2", "http/1.1"}) {
+		t.Errorf("wrong alpn protocols, got: %v", f.ALPN)
+	}
+	if len(f.NPN) != 0 {
+		t.Errorf("npn should not be supported, got: %v", f.NPN)
+	}
+	if !f.OCSPMustStaple {
+		t.Errorf("certificate should be must-staple")
+	}
+	if !f.SCTExtension {
+		t.Errorf("scts should be delivered in the tls extension")
+	}
+	if f.Heartbeat {
+		t.Errorf("heartbeat should not be supported")
+	}
+}
+
+func TestCheckFeaturesGoServerDisabled(t *testing.T) {
+	host, port := startFeatureServer(t, &tls.Config{
+		Certificates:           []tls.Certificate{featureCertificate(t, false)},
+		MinVersion:             uint16(etls.VersionTLS12),
+		SessionTicketsDisabled: true,
+	})
+
+	f := CheckFeatures(host, port, featureCiphers, DefaultOptions())
+
+	if f.SessionTickets || f.SessionTicketLifetime != 0 {
+		t.Errorf("session tickets should not be supported")
+	}
+	if len(f.ALPN) != 0 {
+		t.Errorf("alpn should not be supported, got: %v", f.ALPN)
+	}
+	if f.OCSPMustStaple {
+		t.Errorf("certificate should not be must-staple")
+	}
+	if f.SCTExtension {
+		t.Errorf("scts should not be delivered in the tls extension")
+	}
+}
+
+func TestCheckFeaturesFakeServer(t *testing.T) {
+	srv := &fakeFeatureServer{
+		extensions: map[uint16][]byte{
+			extEncryptThenMAC: {},
+			extHeartbeat:      {1},
+			extNextProtoNeg:   []byte("\x02h2\x08http/1.1"),
+		},
+		sessionID: bytes.Repeat([]byte{0xab}, 32),
+	}
+	host, port := srv.start(t)
+
+	f := CheckFeatures(host, port, featureCiphers, DefaultOptions())
+
+	if f.SecureRenegotiation {
+		t.Errorf("secure renegotiation should not be supported")
+	}
+	if f.ExtendedMasterSecret {
+		t.Errorf("extended master secret should not be supported")
+	}
+	if !f.EncryptThenMAC {
+		t.Errorf("encrypt-then-mac should be supported")
+	}
+	if !f.Heartbeat {
+		t.Errorf("heartbeat should be supported")
+	}
+	if !reflect.DeepEqual(f.NPN, []string{"h2", "http/1.1"}) {
+		t.Errorf("wrong npn protocols, got: %v", f.NPN)
+	}
+	if !f.SessionIDResumption {
+		t.Errorf("session id resumption should be supported")
+	}
+}
+
+func TestCheckFeaturesNoLegacyProtocol(t *testing.T) {
+	host, port := startFeatureServer(t, &tls.Config{
+		Certificates: []tls.Certificate{featureCertificate(t, false)},
+		MinVersion:   uint16(etls.VersionTLS13),
+		NextProtos:   []string{"h2"},
+	})
+
+	f := CheckFeatures(host, port, map[string][]string{"TLSv1.3": {"TLS_AES_128_GCM_SHA256"}}, DefaultOptions())
+
+	if f.SecureRenegotiation || f.SessionTickets || f.SessionIDResumption {
+		t.Errorf("tls 1.2 features should not be checked, got: %+v", f)
+	}
+	if !reflect.DeepEqual(f.ALPN, []string{"h2"}) {
+		t.Errorf("wrong alpn protocols, got: %v", f.ALPN)
+	}
+}
+
+func TestCheckFeaturesLegacyCiphers(t *testing.T) {
+	// neither cipher is in the default suites of crypto/tls
+	ciphers := []uint16{etls.TLS_RSA_WITH_AES_128_CBC_SHA, etls.TLS_RSA_WITH_3DES_EDE_CBC_SHA}
+
+	host, port := startFeatureServer(t, &tls.Config{
+		Certificates: []tls.Certificate{rsaCertificate(t, testRSAKey(t))},
+		MaxVersion:   uint16(etls.VersionTLS12),
+		CipherSuites: ciphers,
+		NextProtos:   []string{"http/1.1"},
+	})
+
+	f := CheckFeatures(host, port, map[string][]string{"TLSv1.2": cipherStrList(ciphers)}, DefaultOptions())
+
+	if !f.SessionTickets {
+		t.Errorf("session tickets should be supported")
+	}
+	if !reflect.DeepEqual(f.ALPN, []string{"http/1.1"}) {
+		t.Errorf("wrong alpn protocols, got: %v", f.ALPN)
+	}
+}
+
+func TestParseALPN(t *testing.T) {
+	if proto := parseALPN(alpnData([]string{"h2"})); proto != "h2" {
+		t.Errorf("wrong protocol, got: %s, want: %s", proto, "h2")
+	}
+	if proto := parseALPN([]byte{0, 3, 2, 'h'}); proto != "" {
+		t.Errorf("truncated extension should be empty, got: %s", proto)
+	}
+}
+
+func TestClearHandshakeMessages(t *testing.T) {
+	ticket := append([]byte{0, 0, 0x1c, 0x20}, 0, 2, 0xaa, 0xbb)
+
+	var b []byte
+	b = append(b, record(recordTypeHandshake, etls.VersionTLS12, handshakeMessage(typeServerHelloDone, nil))...)
+	b = append(b, record(recordTypeHandshake, etls.VersionTLS12, handshakeMessage(typeNewSessionTicket, ticket))...)
+	b = append(b, record(recordTypeChangeCipherSpec, etls.VersionTLS12, []byte{1})...)
+	b = append(b, record(recordTypeHandshake, etls.VersionTLS12, []byte{0xde, 0xad, 0xbe, 0xef})...)
+
+	msgs := clearHandshakeMessages(b)
+	if len(msgs) != 2 {
+		t.Fatalf("wrong message count, got: %d, want: %d", len(msgs), 2)
+	}
+
+	if msgs[1][0] != typeNewSessionTicket || binary.BigEndian.Uint32(msgs[1][1:5]) != 7200 {
+		t.Errorf("wrong session ticket, got: %x", msgs[1])
+	}
+}
+
+func TestParseNPN(t *testing.T) {
+	if got := parseNPN([]byte("\x02h2\x08http/1.1")); !reflect.DeepEqual(got, []string{"h2", "http/1.1"}) {
+		t.Errorf("wrong npn protocols, got: %v", got)
+	}
+
+	if got := parseNPN([]byte{5, 'h'}); len(got) != 0 {
+		t.Errorf("truncated list should not parse, got: %v", got)
+	}
+}
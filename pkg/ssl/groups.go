@@ -0,0 +1,193 @@
+package ssl
+
+import (
+	"encoding/binary"
+	"math/big"
+	"slices"
+	"sync"
+
+	"github.com/jsandas/etls"
+	logger "github.com/jsandas/gologger"
+)
+
+// namedGroups are the key exchange groups tested by Groups
+var namedGroups = map[uint16]string{
+	23:  "secp256r1",
+	24:  "secp384r1",
+	25:  "secp521r1",
+	26:  "brainpoolP256r1",
+	27:  "brainpoolP384r1",
+	28:  "brainpoolP512r1",
+	29:  "x25519",
+	30:  "x448",
+	31:  "brainpoolP256r1tls13",
+	32:  "brainpoolP384r1tls13",
+	33:  "brainpoolP512r1tls13",
+	256: "ffdhe2048",
+	257: "ffdhe3072",
+	258: "ffdhe4096",
+	259: "ffdhe6144",
+	260: "ffdhe8192",
+}
+
+// ffdheSizes are the prime sizes of the RFC 7919 groups
+var ffdheSizes = map[uint16]int{
+	256: 2048,
+	257: 3072,
+	258: 4096,
+	259: 6144,
+	260: 8192,
+}
+
+func isFFDHE(group uint16) bool {
+	_, ok := ffdheSizes[group]
+	return ok
+}
+
+// Groups returns the named groups accepted by the server for TLS 1.2
+// (supported_groups) and TLS 1.3 (key_share/HelloRetryRequest)
+func Groups(host string, port string, supported map[string][]string, opts Options) map[string][]string {
+	var mutex = &sync.Mutex{}
+	var jobs []func()
+	found := make(map[int][]uint16)
+
+	for _, p := range []int{etls.VersionTLS12, etls.VersionTLS13} {
+		if _, ok := supported[protocolVersionMap[p]]; !ok {
+			continue
+		}
+
+		for group := range namedGroups {
+			if !groupApplies(group, p) {
+				continue
+			}
+
+			jobs = append(jobs, func() {
+				if !groupSupported(host, port, p, group, opts) {
+					return
+				}
+				mutex.Lock()
+				found[p] = append(found[p], group)
+				mutex.Unlock()
+			})
+		}
+	}
+
+	runPool(opts.workers(), jobs)
+
+	groups := make(map[string][]string)
+	for p, ids := range found {
+		slices.Sort(ids)
+		for _, id := range ids {
+			groups[protocolVersionMap[p]] = append(groups[protocolVersionMap[p]], namedGroups[id])
+		}
+	}
+
+	logger.Debugf("event_id=supported_groups results=%v", groups)
+	return groups
+}
+
+// groupApplies filters out groups that are not defined for protocol p
+func groupApplies(group uint16, p int) bool {
+	if p == etls.VersionTLS13 {
+		// the original brainpool code points are TLS 1.2 only
+		return group < 26 || group > 28
+	}
+
+	// the TLS 1.3 brainpool code points are not valid in TLS 1.2
+	return group < 31 || group > 33
+}
+
+// groupSupported performs a handshake offering only group
+func groupSupported(host string, port string, p int, group uint16, opts Options) bool {
+	if p == etls.VersionTLS13 {
+		return tls13GroupSupported(host, port, group, opts)
+	}
+
+	kex := "ECDHE"
+	if isFFDHE(group) {
+		kex = "DHE"
+	}
+
+	hello := newClientHello(host, p, kexCiphers(kex, p))
+	hello.setGroups([]uint16{group})
+
+	flight, err := rawHandshake(host, port, hello, opts)
+	if err != nil || flight.hello == nil || flight.keyExchange == nil {
+		return false
+	}
+
+	if isFFDHE(group) {
+		prime, _, _, ok := parseDHParams(flight.keyExchange)
+		return ok && primeName(new(big.Int).SetBytes(prime)) == namedGroups[group]
+	}
+
+	curve, ok := parseECParams(flight.keyExchange)
+	return ok && curve == group
+}
+
+// tls13GroupSupported offers group without a key share so the server has
+// to answer with a HelloRetryRequest selecting it
+func tls13GroupSupported(host string, port string, group uint16, opts Options) bool {
+	hello := newClientHello(host, etls.VersionTLS13, kexCiphers("any", etls.VersionTLS13))
+	hello.setGroups([]uint16{group})
+	hello.setExtension(extKeyShare, []byte{0, 0})
+
+	flight, err := rawHandshake(host, port, hello, opts)
+	if err != nil || flight.hello == nil {
+		return false
+	}
+
+	share, ok := flight.hello.extensions[extKeyShare]
+	if !ok || len(share) < 2 {
+		return false
+	}
+
+	return binary.BigEndian.Uint16(share[0:2]) == group
+}
+
+// kexCiphers returns the ciphers using key exchange kex that are valid for p
+func kexCiphers(kex string, p int) []uint16 {
+	var ciphers []uint16
+	for _, id := range cipherIDs() {
+		c := cipherSuites[id]
+		if c.keyExhange != kex || c.MinProtoVersion > p {
+			continue
+		}
+		if p == etls.VersionTLS13 && c.MinProtoVersion != p {
+			continue
+		}
+		if p < etls.VersionTLS13 && c.MinProtoVersion == etls.VersionTLS13 {
+			continue
+		}
+		ciphers = append(ciphers, id)
+	}
+	return ciphers
+}
+
+// parseECParams returns the named curve of an ECDHE ServerKeyExchange
+func parseECParams(ske []byte) (uint16, bool) {
+	// curve_type 3 is named_curve
+	if len(ske) < 3 || ske[0] != 3 {
+		return 0, false
+	}
+	return binary.BigEndian.Uint16(ske[1:3]), true
+}
+
+// parseDHParams returns p, g and Ys of a DHE ServerKeyExchange
+func parseDHParams(ske []byte) (p []byte, g []byte, ys []byte, ok bool) {
+	var fields [][]byte
+
+	for i := 0; i < 3; i++ {
+		if len(ske) < 2 {
+			return
+		}
+		l := int(binary.BigEndian.Uint16(ske[0:2]))
+		if len(ske) < 2+l {
+			return
+		}
+		fields = append(fields, ske[2:2+l])
+		ske = ske[2+l:]
+	}
+
+	return fields[0], fields[1], fields[2], true
+}
@@ -0,0 +1,149 @@
+package ssl
+
+import (
+	"bytes"
+	"crypto/tls"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jsandas/etls"
+)
+
+func TestGroups(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Write([]byte("Hello"))
+	}))
+	server.TLS = &tls.Config{
+		MinVersion:       uint16(etls.VersionTLS12),
+		MaxVersion:       uint16(etls.VersionTLS13),
+		CurvePreferences: []tls.CurveID{tls.X25519, tls.CurveP256},
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	host, port, _ := net.SplitHostPort(strings.Replace(server.URL, "https://", "", -1))
+
+	supported := map[string][]string{"TLSv1.2": {}, "TLSv1.3": {}}
+	groups := Groups(host, port, supported, DefaultOptions())
+
+	want := []string{"secp256r1", "x25519"}
+	for _, p := range []string{"TLSv1.2", "TLSv1.3"} {
+		if !reflect.DeepEqual(groups[p], want) {
+			t.Errorf("wrong groups for %s, got: %v, want: %v", p, groups[p], want)
+		}
+	}
+}
+
+func TestGroupsSkipsUnsupportedProtocols(t *testing.T) {
+	groups := Groups("127.0.0.1", "1", map[string][]string{"TLSv1.0": {}}, DefaultOptions())
+
+	if len(groups) != 0 {
+		t.Errorf("no groups should be tested, got: %v", groups)
+	}
+}
+
+func TestParseDHParams(t *testing.T) {
+	ske := []byte{0, 2, 0xaa, 0xbb, 0, 1, 2, 0, 1, 0xcc, 0xff}
+
+	p, g, ys, ok := parseDHParams(ske)
+	if !ok {
+		t.Fatalf("failed to parse dh params")
+	}
+
+	if !bytes.Equal(p, []byte{0xaa, 0xbb}) || !bytes.Equal(g, []byte{2}) || !bytes.Equal(ys, []byte{0xcc}) {
+		t.Errorf("wrong dh params, got: p=%x g=%x ys=%x", p, g, ys)
+	}
+
+	if _, _, _, ok = parseDHParams(ske[:5]); ok {
+		t.Errorf("truncated dh params should fail")
+	}
+}
+
+func TestGroupSupportedFFDHE(t *testing.T) {
+	tests := map[string]struct {
+		prime *big.Int
+		group uint16
+		want  bool
+	}{
+		"ffdhe2048":         {prime: wellKnownPrime("ffdhe2048"), group: 256, want: true},
+		"other ffdhe group": {prime: wellKnownPrime("ffdhe2048"), group: 257},
+		"custom prime":      {prime: new(big.Int).Lsh(big.NewInt(1), 2047), group: 256},
+	}
+
+	for name, tc := range tests {
+		s := &fakeFeatureServer{keyExchange: dhServerKeyExchange(tc.prime)}
+		host, port := s.start(t)
+
+		if got := groupSupported(host, port, etls.VersionTLS12, tc.group, DefaultOptions()); got != tc.want {
+			t.Errorf("%s: wrong result, got: %v, want: %v", name, got, tc.want)
+		}
+	}
+}
+
+func TestGroupSupportedBrainpool(t *testing.T) {
+	// ECDHE ServerKeyExchange with named curve brainpoolP256r1
+	s := &fakeFeatureServer{keyExchange: []byte{3, 0, 26, 1, 4}}
+	host, port := s.start(t)
+
+	if !groupSupported(host, port, etls.VersionTLS12, 26, DefaultOptions()) {
+		t.Errorf("brainpoolP256r1 should be supported")
+	}
+	if groupSupported(host, port, etls.VersionTLS12, 23, DefaultOptions()) {
+		t.Errorf("secp256r1 should not be supported")
+	}
+}
+
+func TestGroupSupportedTLS13WithoutKeyShare(t *testing.T) {
+	// groups without an entry in keyShareCurves are detected from the
+	// HelloRetryRequest alone
+	for _, group := range []uint16{30, 31} {
+		s := &fakeFeatureServer{
+			random: helloRetryRandom,
+			extensions: map[uint16][]byte{
+				extSupportedVersions: uint16Bytes(etls.VersionTLS13),
+				extKeyShare:          uint16Bytes(group),
+			},
+		}
+		host, port := s.start(t)
+
+		if !groupSupported(host, port, etls.VersionTLS13, group, DefaultOptions()) {
+			t.Errorf("%s should be supported", namedGroups[group])
+		}
+		if groupSupported(host, port, etls.VersionTLS13, 29, DefaultOptions()) {
+			t.Errorf("x25519 should not be supported when %s is selected", namedGroups[group])
+		}
+
+		// a key share can not be generated for the selected group
+		hello := newClientHello(host, etls.VersionTLS13, kexCiphers("any", etls.VersionTLS13))
+		if _, err := rawHandshake13(host, port, hello, DefaultOptions()); err == nil || !strings.Contains(err.Error(), "unsupported_group") {
+			t.Errorf("%s: wrong error, got: %v", namedGroups[group], err)
+		}
+	}
+}
+
+func TestGroupApplies(t *testing.T) {
+	tests := []struct {
+		group uint16
+		p     int
+		want  bool
+	}{
+		{26, etls.VersionTLS12, true},
+		{26, etls.VersionTLS13, false},
+		{31, etls.VersionTLS12, false},
+		{31, etls.VersionTLS13, true},
+		{30, etls.VersionTLS12, true},
+		{30, etls.VersionTLS13, true},
+		{256, etls.VersionTLS13, true},
+	}
+
+	for _, tc := range tests {
+		if got := groupApplies(tc.group, tc.p); got != tc.want {
+			t.Errorf("%s %s: wrong result, got: %v, want: %v", namedGroups[tc.group], protocolVersionMap[tc.p], got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,528 @@
+package ssl
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/jsandas/etls"
+	logger "github.com/jsandas/gologger"
+	"github.com/jsandas/tlstools/pkg/utils"
+)
+
+/*
+	Raw handshake helpers used by checks that need control over the
+	ClientHello or access to handshake messages that the etls client
+	does not expose (ServerKeyExchange, HelloRetryRequest, alerts).
+	Only the cleartext part of the handshake is processed.
+*/
+
+// TLS record types
+const (
+	recordTypeChangeCipherSpec = 20
+	recordTypeAlert            = 21
+	recordTypeHandshake        = 22
+)
+
+// TLS handshake message types
+const (
+	typeClientHello       = 1
+	typeServerHello       = 2
+	typeNewSessionTicket  = 4
+	typeCertificate       = 11
+	typeServerKeyExchange = 12
+	typeServerHelloDone   = 14
+)
+
+// TLS extension types
+const (
+	extServerName           = 0
+	extSupportedGroups      = 10
+	extECPointFormats       = 11
+	extSignatureAlgorithms  = 13
+	extHeartbeat            = 15
+	extALPN                 = 16
+	extSCT                  = 18
+	extEncryptThenMAC       = 22
+	extExtendedMasterSecret = 23
+	extSessionTicket        = 35
+	extSupportedVersions    = 43
+	extCookie               = 44
+	extKeyShare             = 51
+	extNextProtoNeg         = 13172
+	extRenegotiationInfo    = 0xff01
+)
+
+// helloRetryRandom is the ServerHello.random value that marks a
+// HelloRetryRequest (RFC 8446 4.1.3)
+var helloRetryRandom = []byte{
+	0xcf, 0x21, 0xad, 0x74, 0xe5, 0x9a, 0x61, 0x11, 0xbe, 0x1d, 0x8c, 0x02, 0x1e, 0x65, 0xb8, 0x91,
+	0xc2, 0xa2, 0x11, 0x16, 0x7a, 0xbb, 0x8c, 0x5e, 0x07, 0x9e, 0x09, 0xe2, 0xc8, 0xa8, 0x33, 0x9c,
+}
+
+// defaultSignatureAlgorithms is offered in every raw ClientHello so servers
+// do not fall back to SHA-1 or abort TLS 1.3 handshakes
+var defaultSignatureAlgorithms = []uint16{
+	0x0403, 0x0503, 0x0603, // ecdsa_secp256r1_sha256, ecdsa_secp384r1_sha384, ecdsa_secp521r1_sha512
+	0x0807, 0x0808, // ed25519, ed448
+	0x0804, 0x0805, 0x0806, // rsa_pss_rsae_sha256/384/512
+	0x0809, 0x080a, 0x080b, // rsa_pss_pss_sha256/384/512
+	0x0401, 0x0501, 0x0601, // rsa_pkcs1_sha256/384/512
+	0x0303, 0x0301, 0x0302, 0x0402, 0x0201, 0x0203, 0x0202, // legacy sha224/sha1/dsa
+}
+
+var errMessageTooShort = errors.New("handshake_message_too_short")
+
+// alertError is returned when the server answers with a TLS alert
+type alertError struct {
+	level       uint8
+	description uint8
+}
+
+func (a alertError) Error() string {
+	return fmt.Sprintf("tls_alert level=%d description=%d", a.level, a.description)
+}
+
+// extension is a raw ClientHello extension
+type extension struct {
+	id   uint16
+	data []byte
+}
+
+// clientHello holds the fields of a raw ClientHello
+type clientHello struct {
+	version     uint16
+	random      []byte
+	sessionID   []byte
+	ciphers     []uint16
+	compression []uint8
+	extensions  []extension
+}
+
+// newClientHello returns a ClientHello for protocol p offering ciphers.
+// TLS 1.3 hellos get a legacy version of TLS 1.2 and supported_versions.
+func newClientHello(host string, p int, ciphers []uint16) *clientHello {
+	random, _ := utils.GenRandBytes(32)
+
+	h := &clientHello{
+		version:     uint16(p),
+		random:      random,
+		ciphers:     ciphers,
+		compression: []uint8{0},
+	}
+
+	if p == etls.VersionSSL30 {
+		return h
+	}
+
+	if net.ParseIP(host) == nil && host != "" {
+		h.setExtension(extServerName, serverNameData(host))
+	}
+	h.setExtension(extSignatureAlgorithms, uint16ListData(defaultSignatureAlgorithms))
+
+	if p >= etls.VersionTLS13 {
+		h.version = etls.VersionTLS12
+		// a 32 byte session id keeps middlebox compatibility
+		h.sessionID, _ = utils.GenRandBytes(32)
+		h.setExtension(extSupportedVersions, append([]byte{2}, uint16Bytes(uint16(p))...))
+	}
+
+	return h
+}
+
+// setExtension adds or replaces an extension
+func (h *clientHello) setExtension(id uint16, data []byte) {
+	for i := range h.extensions {
+		if h.extensions[i].id == id {
+			h.extensions[i].data = data
+			return
+		}
+	}
+	h.extensions = append(h.extensions, extension{id: id, data: data})
+}
+
+// setGroups sets supported_groups and ec_point_formats
+func (h *clientHello) setGroups(groups []uint16) {
+	h.setExtension(extSupportedGroups, uint16ListData(groups))
+	h.setExtension(extECPointFormats, []byte{1, 0})
+}
+
+// setStartTLS offers the ALPN protocols required by opts
+func (h *clientHello) setStartTLS(opts StartTLSOptions) {
+	if protos := opts.alpn(); protos != nil && h.version != etls.VersionSSL30 {
+		h.setExtension(extALPN, alpnData(protos))
+	}
+}
+
+// keyShareCurves are the groups a key share can be generated for
+var keyShareCurves = map[uint16]ecdh.Curve{
+	23: ecdh.P256(),
+	24: ecdh.P384(),
+	25: ecdh.P521(),
+	29: ecdh.X25519(),
+}
+
+// setKeyShares sets key_share with a fresh public key for each group
+func (h *clientHello) setKeyShares(groups []uint16) {
+	shares := new(bytes.Buffer)
+	for _, g := range groups {
+		curve, ok := keyShareCurves[g]
+		if !ok {
+			continue
+		}
+		key, err := curve.GenerateKey(rand.Reader)
+		if err != nil {
+			continue
+		}
+		pub := key.PublicKey().Bytes()
+		shares.Write(uint16Bytes(g))
+		shares.Write(uint16Bytes(uint16(len(pub))))
+		shares.Write(pub)
+	}
+
+	h.setExtension(extKeyShare, append(uint16Bytes(uint16(shares.Len())), shares.Bytes()...))
+}
+
+// body returns the encoded ClientHello handshake message
+func (h *clientHello) body() []byte {
+	b := new(bytes.Buffer)
+
+	b.Write(uint16Bytes(h.version))
+	b.Write(h.random)
+	b.WriteByte(uint8(len(h.sessionID)))
+	b.Write(h.sessionID)
+	b.Write(uint16ListData(h.ciphers))
+	b.WriteByte(uint8(len(h.compression)))
+	b.Write(h.compression)
+
+	if len(h.extensions) > 0 {
+		ext := new(bytes.Buffer)
+		for _, e := range h.extensions {
+			ext.Write(uint16Bytes(e.id))
+			ext.Write(uint16Bytes(uint16(len(e.data))))
+			ext.Write(e.data)
+		}
+		b.Write(uint16Bytes(uint16(ext.Len())))
+		b.Write(ext.Bytes())
+	}
+
+	return handshakeMessage(typeClientHello, b.Bytes())
+}
+
+// marshal returns the ClientHello wrapped in a record
+func (h *clientHello) marshal() []byte {
+	recordVersion := uint16(etls.VersionTLS10)
+	if h.version == etls.VersionSSL30 {
+		recordVersion = etls.VersionSSL30
+	}
+
+	return record(recordTypeHandshake, recordVersion, h.body())
+}
+
+// handshakeMessage adds the handshake header to body
+func handshakeMessage(typ uint8, body []byte) []byte {
+	l := len(body)
+	return append([]byte{typ, byte(l >> 16), byte(l >> 8), byte(l)}, body...)
+}
+
+// record adds the record header to fragment
+func record(typ uint8, version uint16, fragment []byte) []byte {
+	b := []byte{typ}
+	b = append(b, uint16Bytes(version)...)
+	b = append(b, uint16Bytes(uint16(len(fragment)))...)
+	return append(b, fragment...)
+}
+
+func uint16Bytes(v uint16) []byte {
+	return []byte{byte(v >> 8), byte(v)}
+}
+
+// uint16ListData encodes a list of uint16 with a 2 byte length prefix
+func uint16ListData(l []uint16) []byte {
+	b := uint16Bytes(uint16(len(l) * 2))
+	for _, v := range l {
+		b = append(b, uint16Bytes(v)...)
+	}
+	return b
+}
+
+// alpnData encodes the application_layer_protocol_negotiation extension
+func alpnData(protos []string) []byte {
+	var list []byte
+	for _, p := range protos {
+		list = append(list, byte(len(p)))
+		list = append(list, p...)
+	}
+	return append(uint16Bytes(uint16(len(list))), list...)
+}
+
+// serverNameData encodes the server_name extension for host
+func serverNameData(host string) []byte {
+	name := append([]byte{0}, uint16Bytes(uint16(len(host)))...)
+	name = append(name, host...)
+	return append(uint16Bytes(uint16(len(name))), name...)
+}
+
+// serverHello holds the parsed fields of a ServerHello
+type serverHello struct {
+	version     uint16
+	random      []byte
+	sessionID   []byte
+	cipher      uint16
+	compression uint8
+	extensions  map[uint16][]byte
+}
+
+// isHelloRetry reports if the ServerHello is a HelloRetryRequest
+func (s *serverHello) isHelloRetry() bool {
+	return bytes.Equal(s.random, helloRetryRandom)
+}
+
+// parseServerHello parses a ServerHello handshake body
+func parseServerHello(b []byte) (*serverHello, error) {
+	s := &serverHello{extensions: make(map[uint16][]byte)}
+
+	if len(b) < 35 {
+		return nil, errMessageTooShort
+	}
+	s.version = binary.BigEndian.Uint16(b[0:2])
+	s.random = b[2:34]
+
+	sidLen := int(b[34])
+	b = b[35:]
+	if len(b) < sidLen+3 {
+		return nil, errMessageTooShort
+	}
+	s.sessionID = b[:sidLen]
+	s.cipher = binary.BigEndian.Uint16(b[sidLen : sidLen+2])
+	s.compression = b[sidLen+2]
+	b = b[sidLen+3:]
+
+	if len(b) < 2 {
+		return s, nil
+	}
+
+	extLen := int(binary.BigEndian.Uint16(b[0:2]))
+	b = b[2:]
+	if len(b) < extLen {
+		return nil, errMessageTooShort
+	}
+	b = b[:extLen]
+
+	for len(b) >= 4 {
+		id := binary.BigEndian.Uint16(b[0:2])
+		l := int(binary.BigEndian.Uint16(b[2:4]))
+		if len(b) < 4+l {
+			return nil, errMessageTooShort
+		}
+		s.extensions[id] = b[4 : 4+l]
+		b = b[4+l:]
+	}
+
+	if v, ok := s.extensions[extSupportedVersions]; ok && len(v) == 2 {
+		s.version = binary.BigEndian.Uint16(v)
+	}
+
+	return s, nil
+}
+
+// serverFlight is the cleartext part of the server's handshake
+type serverFlight struct {
+	hello        *serverHello
+	certificates [][]byte
+	keyExchange  []byte
+	helloDone    bool
+}
+
+// handshakeReader returns handshake messages reassembled from records
+type handshakeReader struct {
+	r   io.Reader
+	buf []byte
+}
+
+// readRecord reads a single record from r
+func readRecord(r io.Reader) (typ uint8, version uint16, fragment []byte, err error) {
+	header := make([]byte, 5)
+	if _, err = io.ReadFull(r, header); err != nil {
+		return
+	}
+
+	typ = header[0]
+	version = binary.BigEndian.Uint16(header[1:3])
+	fragment = make([]byte, binary.BigEndian.Uint16(header[3:5]))
+	_, err = io.ReadFull(r, fragment)
+
+	return
+}
+
+// next returns the next handshake message. Alerts are returned as alertError
+func (h *handshakeReader) next() (typ uint8, body []byte, err error) {
+	for {
+		if len(h.buf) >= 4 {
+			l := int(h.buf[1])<<16 | int(h.buf[2])<<8 | int(h.buf[3])
+			if len(h.buf) >= 4+l {
+				typ = h.buf[0]
+				body = h.buf[4 : 4+l]
+				h.buf = h.buf[4+l:]
+				return
+			}
+		}
+
+		rtyp, _, fragment, rerr := readRecord(h.r)
+		if rerr != nil {
+			return 0, nil, rerr
+		}
+
+		switch rtyp {
+		case recordTypeHandshake:
+			h.buf = append(h.buf, fragment...)
+		case recordTypeAlert:
+			if len(fragment) < 2 {
+				return 0, nil, errMessageTooShort
+			}
+			return 0, nil, alertError{level: fragment[0], description: fragment[1]}
+		default:
+			// TLS 1.3 compatibility ChangeCipherSpec and anything
+			// else outside of the handshake is skipped
+		}
+	}
+}
+
+// readServerFlight reads server messages until ServerHelloDone or, for
+// TLS 1.3 and HelloRetryRequest, until the ServerHello
+func readServerFlight(r io.Reader) (*serverFlight, error) {
+	flight := &serverFlight{}
+	hr := &handshakeReader{r: r}
+
+	for {
+		typ, body, err := hr.next()
+		if err != nil {
+			return flight, err
+		}
+
+		switch typ {
+		case typeServerHello:
+			if flight.hello, err = parseServerHello(body); err != nil {
+				return flight, err
+			}
+			if flight.hello.version >= etls.VersionTLS13 || flight.hello.isHelloRetry() {
+				return flight, nil
+			}
+		case typeCertificate:
+			flight.certificates = parseCertificates(body)
+		case typeServerKeyExchange:
+			flight.keyExchange = body
+		case typeServerHelloDone:
+			flight.helloDone = true
+			return flight, nil
+		}
+	}
+}
+
+// parseCertificates returns the DER certificates of a TLS 1.2 Certificate message
+func parseCertificates(b []byte) [][]byte {
+	var certs [][]byte
+
+	if len(b) < 3 {
+		return certs
+	}
+	b = b[3:]
+
+	for len(b) >= 3 {
+		l := int(b[0])<<16 | int(b[1])<<8 | int(b[2])
+		if len(b) < 3+l {
+			break
+		}
+		certs = append(certs, b[3:3+l])
+		b = b[3+l:]
+	}
+
+	return certs
+}
+
+// rawHandshake sends hello and reads the server's response
+func rawHandshake(host string, port string, hello *clientHello, opts Options) (*serverFlight, error) {
+	conn, err := rawDial(host, port, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	hello.setStartTLS(opts.StartTLS)
+	return rawExchange(conn, hello)
+}
+
+// rawHandshake13 sends a TLS 1.3 hello offering the key share curves with
+// x25519 and secp256r1 shares. If the server asks for another group in a
+// HelloRetryRequest the hello is sent again with a share for that group
+func rawHandshake13(host string, port string, hello *clientHello, opts Options) (*serverFlight, error) {
+	conn, err := rawDial(host, port, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	hello.setExtension(extSupportedGroups, uint16ListData([]uint16{29, 23, 24, 25}))
+	hello.setKeyShares([]uint16{29, 23})
+	hello.setStartTLS(opts.StartTLS)
+
+	flight, err := rawExchange(conn, hello)
+	if err != nil || flight.hello == nil || !flight.hello.isHelloRetry() {
+		return flight, err
+	}
+
+	share := flight.hello.extensions[extKeyShare]
+	if len(share) < 2 {
+		return flight, errMessageTooShort
+	}
+	group := binary.BigEndian.Uint16(share[0:2])
+	if _, ok := keyShareCurves[group]; !ok {
+		return flight, fmt.Errorf("unsupported_group group=%d", group)
+	}
+
+	hello.setKeyShares([]uint16{group})
+	if cookie, ok := flight.hello.extensions[extCookie]; ok {
+		hello.setExtension(extCookie, cookie)
+	}
+
+	return rawExchange(conn, hello)
+}
+
+// rawDial opens a tcp connection and performs StartTLS if required
+func rawDial(host string, port string, opts Options) (net.Conn, error) {
+	conn, err := opts.dial(host, port, 3*time.Second)
+	if err != nil {
+		logger.Debugf("event_id=tcp_dial_failed server=%s:%s msg\"%v\"", host, port, err)
+		return nil, err
+	}
+
+	err = StartTLSWithOptions(conn, host, port, opts.StartTLS)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+// rawExchange writes hello to conn and reads the server's response
+func rawExchange(conn net.Conn, hello *clientHello) (*serverFlight, error) {
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	if _, err := conn.Write(hello.marshal()); err != nil {
+		return nil, err
+	}
+
+	flight, err := readServerFlight(conn)
+	if err != nil {
+		logger.Debugf("event_id=raw_handshake_failed msg=\"%v\"", err)
+	}
+
+	return flight, err
+}
@@ -0,0 +1,108 @@
+package ssl
+
+import (
+	"bytes"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jsandas/etls"
+)
+
+func TestClientHelloMarshal(t *testing.T) {
+	hello := newClientHello("example.com", etls.VersionTLS12, []uint16{etls.TLS_RSA_WITH_AES_128_CBC_SHA})
+
+	b := hello.marshal()
+
+	if b[0] != recordTypeHandshake || b[5] != typeClientHello {
+		t.Fatalf("wrong record/message type, got: %x", b[:6])
+	}
+
+	if l := int(b[3])<<8 | int(b[4]); l != len(b)-5 {
+		t.Errorf("wrong record length, got: %d, want: %d", l, len(b)-5)
+	}
+
+	if !bytes.Contains(b, []byte("example.com")) {
+		t.Errorf("server name missing from hello")
+	}
+}
+
+func TestClientHelloSSLv3(t *testing.T) {
+	hello := newClientHello("example.com", etls.VersionSSL30, []uint16{etls.TLS_RSA_WITH_AES_128_CBC_SHA})
+
+	if len(hello.extensions) != 0 {
+		t.Errorf("sslv3 hello should not have extensions, got: %d", len(hello.extensions))
+	}
+
+	if b := hello.marshal(); b[1] != 3 || b[2] != 0 {
+		t.Errorf("wrong record version, got: %x", b[1:3])
+	}
+}
+
+func TestParseServerHello(t *testing.T) {
+	body := []byte{3, 3}
+	body = append(body, helloRetryRandom...)
+	body = append(body, 0)          // session id
+	body = append(body, 0x13, 0x01) // cipher
+	body = append(body, 0)          // compression
+	body = append(body, 0, 12)      // extensions
+	body = append(body, 0, extSupportedVersions, 0, 2, 3, 4)
+	body = append(body, 0, extKeyShare, 0, 2, 0, 29)
+
+	s, err := parseServerHello(body)
+	if err != nil {
+		t.Fatalf("failed to parse server hello: %v", err)
+	}
+
+	if !s.isHelloRetry() {
+		t.Errorf("server hello should be a hello retry request")
+	}
+
+	if s.version != etls.VersionTLS13 {
+		t.Errorf("wrong version, got: %x, want: %x", s.version, etls.VersionTLS13)
+	}
+
+	if s.cipher != 0x1301 {
+		t.Errorf("wrong cipher, got: %x, want: %x", s.cipher, 0x1301)
+	}
+
+	if _, err = parseServerHello(body[:20]); err == nil {
+		t.Errorf("truncated server hello should fail")
+	}
+}
+
+func TestRawHandshake(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Write([]byte("Hello"))
+	}))
+	server.TLS = &tls.Config{
+		MinVersion: uint16(etls.VersionTLS12),
+		MaxVersion: uint16(etls.VersionTLS12),
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	host, port, _ := net.SplitHostPort(strings.Replace(server.URL, "https://", "", -1))
+
+	hello := newClientHello(host, etls.VersionTLS12, []uint16{etls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256})
+	hello.setGroups([]uint16{29})
+
+	flight, err := rawHandshake(host, port, hello, DefaultOptions())
+	if err != nil {
+		t.Fatalf("handshake failed: %v", err)
+	}
+
+	if !flight.helloDone || len(flight.certificates) == 0 || flight.keyExchange == nil {
+		t.Errorf("incomplete server flight, got: %+v", flight)
+	}
+
+	// no shared cipher
+	hello = newClientHello(host, etls.VersionTLS12, []uint16{etls.TLS_RSA_WITH_RC4_128_MD5})
+	_, err = rawHandshake(host, port, hello, DefaultOptions())
+	if _, ok := err.(alertError); !ok {
+		t.Errorf("expected an alert, got: %v", err)
+	}
+}
@@ -0,0 +1,246 @@
+package ssl
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/jsandas/etls"
+	logger "github.com/jsandas/gologger"
+)
+
+// extPadding is the ClientHello padding extension (RFC 7685)
+const extPadding = 21
+
+// GREASE values (RFC 8701)
+const (
+	greaseCipher     = 0x0a0a
+	greaseExtension  = 0x1a1a
+	greaseExtension2 = 0x4a4a
+	greaseGroup      = 0x2a2a
+	greaseVersion    = 0x3a3a
+)
+
+// versionTLS14 is a protocol version that does not exist yet
+const versionTLS14 = 0x0305
+
+// baselineCiphers are widely supported ciphers offered by every probe so
+// the ClientHello stays small unless a probe pads it
+var baselineCiphers = []uint16{
+	etls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	etls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	etls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	etls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	etls.TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA,
+	etls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA,
+	etls.TLS_DHE_RSA_WITH_AES_128_CBC_SHA,
+	etls.TLS_RSA_WITH_AES_128_GCM_SHA256,
+	etls.TLS_RSA_WITH_AES_128_CBC_SHA,
+	etls.TLS_RSA_WITH_AES_256_CBC_SHA,
+	etls.TLS_RSA_WITH_3DES_EDE_CBC_SHA,
+}
+
+// intoleranceProbe alters the baseline ClientHello. applies reports if the
+// altered hello tests the probe, check validates the ServerHello of a
+// tolerant server
+type intoleranceProbe struct {
+	name    string
+	modify  func(h *clientHello)
+	applies func(h *clientHello) bool
+	check   func(s *serverHello) bool
+}
+
+var intoleranceProbes = []intoleranceProbe{
+	{
+		name:   "futureVersion",
+		modify: func(h *clientHello) { h.version = versionTLS14 },
+		check:  func(s *serverHello) bool { return s.version <= etls.VersionTLS12 },
+	},
+	{
+		name: "futureSupportedVersion",
+		modify: func(h *clientHello) {
+			h.setExtension(extSupportedVersions, supportedVersionsData(versionTLS14, etls.VersionTLS12))
+		},
+		check: func(s *serverHello) bool { return s.version <= etls.VersionTLS13 },
+	},
+	{
+		name:   "greaseCipher",
+		modify: func(h *clientHello) { h.ciphers = append([]uint16{greaseCipher}, h.ciphers...) },
+		check:  func(s *serverHello) bool { return s.cipher != greaseCipher },
+	},
+	{
+		name: "greaseExtension",
+		modify: func(h *clientHello) {
+			h.extensions = append([]extension{{id: greaseExtension}}, h.extensions...)
+			h.setExtension(greaseExtension2, []byte{0})
+		},
+		check: func(s *serverHello) bool {
+			_, first := s.extensions[greaseExtension]
+			_, second := s.extensions[greaseExtension2]
+			return !first && !second
+		},
+	},
+	{
+		name: "greaseGroup",
+		modify: func(h *clientHello) {
+			h.setGroups(append([]uint16{greaseGroup}, kexGroups(etls.VersionTLS12)...))
+		},
+	},
+	{
+		name: "greaseVersion",
+		modify: func(h *clientHello) {
+			h.setExtension(extSupportedVersions, supportedVersionsData(greaseVersion, etls.VersionTLS12))
+		},
+		check: func(s *serverHello) bool { return s.version != greaseVersion },
+	},
+	{
+		// the F5 bug: hellos of 256 to 511 bytes are dropped. A long
+		// server name may push the hello past 511 bytes
+		name:   "size256to511",
+		modify: func(h *clientHello) { h.padTo(300) },
+		applies: func(h *clientHello) bool {
+			l := len(h.body())
+			return l >= 256 && l <= 511
+		},
+	},
+	{
+		name:    "sizeOver512",
+		modify:  func(h *clientHello) { h.padTo(600) },
+		applies: func(h *clientHello) bool { return len(h.body()) >= 512 },
+	},
+	{
+		name:   "reversedExtensions",
+		modify: func(h *clientHello) { slices.Reverse(h.extensions) },
+	},
+	{
+		// some servers fail if the last extension is empty
+		name:   "emptyLastExtension",
+		modify: func(h *clientHello) { h.setExtension(extExtendedMasterSecret, []byte{}) },
+	},
+}
+
+// IntoleranceProbe is the result of a modified ClientHello
+type IntoleranceProbe struct {
+	Name string `json:"name"`
+	// Tolerant is false for intolerant servers and probes that were not
+	// sent
+	Tolerant bool `json:"tolerant"`
+	// Result is the negotiated protocol, the reason the probe failed or
+	// n/a when the hello could not be altered as the probe requires
+	Result string `json:"result"`
+}
+
+// Intolerance lists the ClientHello variants the server mishandles
+type Intolerance struct {
+	Probes     []IntoleranceProbe `json:"probes"`
+	Intolerant []string           `json:"intolerant"`
+}
+
+// CheckIntolerance sends ClientHellos with future versions, GREASE values,
+// unusual sizes and extension orders and compares the result with a
+// baseline TLS 1.2 hello
+func CheckIntolerance(host string, port string, opts Options) Intolerance {
+	var mutex = &sync.Mutex{}
+	var jobs []func()
+	var in = Intolerance{Intolerant: []string{}}
+
+	if baseline := runIntoleranceProbe(host, port, intoleranceProbe{name: "baseline"}, opts); !baseline.Tolerant {
+		logger.Debugf("event_id=intolerance_baseline_failed result=%s", baseline.Result)
+		return in
+	}
+
+	in.Probes = make([]IntoleranceProbe, len(intoleranceProbes))
+	for i, probe := range intoleranceProbes {
+		jobs = append(jobs, func() {
+			res := runIntoleranceProbe(host, port, probe, opts)
+			mutex.Lock()
+			in.Probes[i] = res
+			mutex.Unlock()
+		})
+	}
+
+	runPool(opts.workers(), jobs)
+
+	for _, p := range in.Probes {
+		if !p.Tolerant && p.Result != notApplicable {
+			in.Intolerant = append(in.Intolerant, p.Name)
+		}
+	}
+
+	logger.Debugf("event_id=intolerance results=%v", in.Intolerant)
+	return in
+}
+
+// runIntoleranceProbe sends the baseline hello modified by probe
+func runIntoleranceProbe(host string, port string, probe intoleranceProbe, opts Options) IntoleranceProbe {
+	var res = IntoleranceProbe{Name: probe.name}
+
+	hello, ok := intoleranceHello(host, probe, opts)
+	if !ok {
+		logger.Debugf("event_id=intolerance_probe_not_applicable probe=%s size=%d", probe.name, len(hello.body()))
+		res.Result = notApplicable
+		return res
+	}
+
+	flight, err := rawHandshake(host, port, hello, opts)
+	if flight == nil || flight.hello == nil {
+		res.Result = intoleranceError(err)
+		return res
+	}
+
+	res.Result = protocolVersionMap[int(flight.hello.version)]
+	if res.Result == "" {
+		res.Result = fmt.Sprintf("unknown version %#04x", flight.hello.version)
+	}
+
+	res.Tolerant = probe.check == nil || probe.check(flight.hello)
+
+	return res
+}
+
+// intoleranceHello returns the baseline hello altered by probe and if it
+// tests the probe
+func intoleranceHello(host string, probe intoleranceProbe, opts Options) (*clientHello, bool) {
+	hello := newClientHello(host, etls.VersionTLS12, slices.Clone(baselineCiphers))
+	hello.setGroups(kexGroups(etls.VersionTLS12))
+	if probe.modify != nil {
+		probe.modify(hello)
+	}
+	// rawHandshake adds the StartTLS extensions, which count towards the size
+	hello.setStartTLS(opts.StartTLS)
+
+	return hello, probe.applies == nil || probe.applies(hello)
+}
+
+// intoleranceError describes why a probe failed
+func intoleranceError(err error) string {
+	var alert alertError
+	if errors.As(err, &alert) {
+		return fmt.Sprintf("alert %d", alert.description)
+	}
+	if err == nil {
+		return "no server hello"
+	}
+	return err.Error()
+}
+
+// padTo adds a padding extension so the ClientHello message is size bytes
+func (h *clientHello) padTo(size int) {
+	h.setExtension(extPadding, []byte{})
+
+	n := size - len(h.body())
+	if n < 0 {
+		n = 0
+	}
+	h.setExtension(extPadding, make([]byte, n))
+}
+
+// supportedVersionsData encodes the supported_versions extension
+func supportedVersionsData(versions ...uint16) []byte {
+	b := []byte{byte(len(versions) * 2)}
+	for _, v := range versions {
+		b = append(b, uint16Bytes(v)...)
+	}
+	return b
+}
@@ -0,0 +1,97 @@
+package ssl
+
+import (
+	"crypto/tls"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jsandas/etls"
+)
+
+func TestCheckIntolerance(t *testing.T) {
+	host, port := startFeatureServer(t, &tls.Config{
+		Certificates: []tls.Certificate{featureCertificate(t, false)},
+		MinVersion:   uint16(etls.VersionTLS10),
+	})
+
+	in := CheckIntolerance(host, port, DefaultOptions())
+
+	if len(in.Probes) != len(intoleranceProbes) {
+		t.Fatalf("wrong probe count, got: %d, want: %d", len(in.Probes), len(intoleranceProbes))
+	}
+
+	if len(in.Intolerant) != 0 {
+		t.Errorf("server should tolerate every probe, got: %+v", in.Probes)
+	}
+
+	for _, p := range in.Probes {
+		if p.Result != "TLSv1.2" {
+			t.Errorf("wrong result for %s, got: %s, want: TLSv1.2", p.Name, p.Result)
+		}
+	}
+}
+
+func TestCheckIntoleranceFakeServer(t *testing.T) {
+	// drop hellos over 511 bytes and select the first offered cipher,
+	// even if it is a GREASE value
+	srv := &fakeFeatureServer{reject: func(hello []byte) bool { return len(hello) > 511 }}
+	host, port := srv.start(t)
+
+	in := CheckIntolerance(host, port, DefaultOptions())
+
+	want := []string{"greaseCipher", "sizeOver512"}
+	if !reflect.DeepEqual(in.Intolerant, want) {
+		t.Errorf("wrong intolerant probes, got: %v, want: %v", in.Intolerant, want)
+	}
+}
+
+func TestCheckIntoleranceNoServer(t *testing.T) {
+	in := CheckIntolerance("127.0.0.1", "1", DefaultOptions())
+
+	if len(in.Probes) != 0 || len(in.Intolerant) != 0 {
+		t.Errorf("no probes should run without a baseline, got: %+v", in)
+	}
+}
+
+func TestClientHelloPadTo(t *testing.T) {
+	hello := newClientHello("example.com", etls.VersionTLS12, baselineCiphers)
+	hello.setGroups(kexGroups(etls.VersionTLS12))
+
+	if l := len(hello.body()); l >= 256 {
+		t.Fatalf("baseline hello should be below 256 bytes, got: %d", l)
+	}
+
+	for _, size := range []int{300, 600} {
+		hello.padTo(size)
+		if l := len(hello.body()); l != size {
+			t.Errorf("wrong padded size, got: %d, want: %d", l, size)
+		}
+	}
+}
+
+func TestIntoleranceHelloLongServerName(t *testing.T) {
+	// longer than a DNS name may be, the baseline hello stays below 511
+	// bytes otherwise
+	host := strings.Repeat(strings.Repeat("a", 63)+".", 6) + "example.com"
+
+	for _, probe := range intoleranceProbes {
+		hello, ok := intoleranceHello(host, probe, Options{})
+		switch probe.name {
+		case "size256to511":
+			if ok {
+				t.Errorf("%s should not apply to a %d byte hello", probe.name, len(hello.body()))
+			}
+		default:
+			if !ok {
+				t.Errorf("%s should apply, hello size: %d", probe.name, len(hello.body()))
+			}
+		}
+	}
+
+	for _, probe := range intoleranceProbes {
+		if _, ok := intoleranceHello("example.com", probe, Options{}); !ok {
+			t.Errorf("%s should apply to a short server name", probe.name)
+		}
+	}
+}
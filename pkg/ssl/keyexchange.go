@@ -0,0 +1,186 @@
+package ssl
+
+import (
+	"encoding/binary"
+	"math/big"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/jsandas/etls"
+	logger "github.com/jsandas/gologger"
+)
+
+// key exchange types
+const (
+	kexDH   = "DH"
+	kexECDH = "ECDH"
+)
+
+// curveSizes is the strength in bits of the named curves
+var curveSizes = map[uint16]int{
+	23: 256,
+	24: 384,
+	25: 521,
+	26: 256,
+	27: 384,
+	28: 512,
+	29: 253,
+	30: 448,
+	31: 256,
+	32: 384,
+	33: 512,
+}
+
+// KeyExchange is the ephemeral key exchange sent by the server
+type KeyExchange struct {
+	// Type is DH or ECDH
+	Type string `json:"type"`
+	// Size is the prime size for DH or the curve size for ECDH
+	Size int `json:"size"`
+	// Group is the curve name or the name of a well-known DH prime
+	Group string `json:"group,omitempty"`
+	// CommonPrime is true when the DH prime is a published, widely
+	// shared prime
+	CommonPrime bool `json:"commonPrime"`
+	// Logjam is true when the DH prime is at most 512 bits or a
+	// common prime of at most 1024 bits
+	Logjam bool `json:"logjam"`
+}
+
+// KeyExchangeParams performs a handshake for every supported cipher using
+// an ephemeral key exchange and records the parameters used by the server
+func KeyExchangeParams(host string, port string, supported map[string][]string, opts Options) map[string][]CipherResult {
+	var mutex = &sync.Mutex{}
+	var jobs []func()
+	results := make(map[string][]CipherResult)
+
+	for pname, names := range supported {
+		results[pname] = make([]CipherResult, len(names))
+
+		p, ok := protocolVersion(pname)
+
+		for i, name := range names {
+			results[pname][i] = newCipherResult(name)
+
+			id, found := cipherID(name)
+			if !ok || !found || !isEphemeral(cipherSuites[id], p) {
+				continue
+			}
+
+			jobs = append(jobs, func() {
+				kex := getKeyExchange(host, port, p, id, opts)
+				mutex.Lock()
+				results[pname][i].KeyExchangeParams = kex
+				mutex.Unlock()
+			})
+		}
+	}
+
+	runPool(opts.workers(), jobs)
+
+	logger.Debugf("event_id=key_exchange_params results=%v", results)
+	return results
+}
+
+// isEphemeral reports if cipher c uses an ephemeral DH/ECDH key exchange
+func isEphemeral(c cipherSuite, p int) bool {
+	if p == etls.VersionTLS13 {
+		return true
+	}
+	return strings.HasPrefix(c.keyExhange, "DHE") || c.keyExhange == "ECDHE" ||
+		(c.authentication == "None" && c.keyExhange != "RSA")
+}
+
+// getKeyExchange offers only cipher and parses the server's key exchange
+func getKeyExchange(host string, port string, p int, cipher uint16, opts Options) *KeyExchange {
+	groups := kexGroups(p)
+
+	hello := newClientHello(host, p, []uint16{cipher})
+
+	if p == etls.VersionTLS13 {
+		// offer ffdhe as well and no key share so the server's pick is
+		// returned in a HelloRetryRequest
+		for g := range ffdheSizes {
+			groups = append(groups, g)
+		}
+		slices.Sort(groups)
+		hello.setGroups(groups)
+		hello.setExtension(extKeyShare, []byte{0, 0})
+	} else if p > etls.VersionSSL30 {
+		hello.setGroups(groups)
+	}
+
+	flight, err := rawHandshake(host, port, hello, opts)
+	if err != nil || flight.hello == nil {
+		return nil
+	}
+
+	if p == etls.VersionTLS13 {
+		share, ok := flight.hello.extensions[extKeyShare]
+		if !ok || len(share) < 2 {
+			return nil
+		}
+		return groupKeyExchange(binary.BigEndian.Uint16(share[0:2]))
+	}
+
+	return parseKeyExchange(flight.keyExchange, strings.HasPrefix(cipherSuites[cipher].keyExhange, "ECDH"))
+}
+
+// groupKeyExchange describes a TLS 1.3 named group
+func groupKeyExchange(group uint16) *KeyExchange {
+	if bits, ok := ffdheSizes[group]; ok {
+		return &KeyExchange{Type: kexDH, Size: bits, Group: namedGroups[group], CommonPrime: true}
+	}
+
+	if bits, ok := curveSizes[group]; ok {
+		return &KeyExchange{Type: kexECDH, Size: bits, Group: namedGroups[group]}
+	}
+
+	return nil
+}
+
+// parseKeyExchange parses the params of a DHE or ECDHE ServerKeyExchange
+func parseKeyExchange(ske []byte, ecdh bool) *KeyExchange {
+	if len(ske) == 0 {
+		return nil
+	}
+
+	if ecdh {
+		curve, ok := parseECParams(ske)
+		if !ok {
+			return nil
+		}
+		kex := &KeyExchange{Type: kexECDH, Size: curveSizes[curve], Group: namedGroups[curve]}
+		if kex.Group == "" {
+			kex.Group = "unknown"
+		}
+		return kex
+	}
+
+	prime, _, _, ok := parseDHParams(ske)
+	if !ok {
+		return nil
+	}
+
+	p := new(big.Int).SetBytes(prime)
+	kex := &KeyExchange{
+		Type:  kexDH,
+		Size:  p.BitLen(),
+		Group: primeName(p),
+	}
+	kex.CommonPrime = kex.Group != ""
+	kex.Logjam = kex.Size <= 512 || (kex.CommonPrime && kex.Size <= 1024)
+
+	return kex
+}
+
+// cipherID returns the code point of a cipher name
+func cipherID(name string) (uint16, bool) {
+	for id, c := range cipherSuites {
+		if c.name == name {
+			return id, true
+		}
+	}
+	return 0, false
+}
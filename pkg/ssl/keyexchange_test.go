@@ -0,0 +1,99 @@
+package ssl
+
+import (
+	"crypto/tls"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jsandas/etls"
+)
+
+func TestKeyExchangeParams(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Write([]byte("Hello"))
+	}))
+	server.TLS = &tls.Config{
+		MinVersion:       uint16(etls.VersionTLS12),
+		MaxVersion:       uint16(etls.VersionTLS13),
+		CurvePreferences: []tls.CurveID{tls.CurveP384},
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	host, port, _ := net.SplitHostPort(strings.Replace(server.URL, "https://", "", -1))
+
+	supported := map[string][]string{
+		"TLSv1.2": {"TLS_RSA_WITH_AES_128_GCM_SHA256", "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"},
+		"TLSv1.3": {"TLS_AES_128_GCM_SHA256"},
+	}
+	results := KeyExchangeParams(host, port, supported, DefaultOptions())
+
+	tls12 := results["TLSv1.2"]
+	if len(tls12) != 2 {
+		t.Fatalf("wrong cipher count, got: %d, want: %d", len(tls12), 2)
+	}
+
+	if tls12[0].KeyExchangeParams != nil {
+		t.Errorf("static rsa key exchange should not have params, got: %+v", tls12[0].KeyExchangeParams)
+	}
+
+	want := KeyExchange{Type: kexECDH, Size: 384, Group: "secp384r1"}
+	if tls12[1].KeyExchangeParams == nil || *tls12[1].KeyExchangeParams != want {
+		t.Errorf("wrong key exchange, got: %+v, want: %+v", tls12[1].KeyExchangeParams, want)
+	}
+
+	tls13 := results["TLSv1.3"]
+	if len(tls13) != 1 || tls13[0].KeyExchangeParams == nil || *tls13[0].KeyExchangeParams != want {
+		t.Errorf("wrong tls 1.3 key exchange, got: %+v", tls13)
+	}
+}
+
+func dhServerKeyExchange(p *big.Int) []byte {
+	ske := append(uint16Bytes(uint16(len(p.Bytes()))), p.Bytes()...)
+	ske = append(ske, 0, 1, 2)
+	return append(ske, 0, 1, 5)
+}
+
+func TestParseKeyExchange(t *testing.T) {
+	tests := map[string]struct {
+		prime *big.Int
+		want  KeyExchange
+	}{
+		"common 1024": {
+			prime: wellKnownPrime("rfc2409-1024"),
+			want:  KeyExchange{Type: kexDH, Size: 1024, Group: "rfc2409-1024", CommonPrime: true, Logjam: true},
+		},
+		"common 2048": {
+			prime: wellKnownPrime("ffdhe2048"),
+			want:  KeyExchange{Type: kexDH, Size: 2048, Group: "ffdhe2048", CommonPrime: true},
+		},
+		"export": {
+			prime: new(big.Int).Lsh(big.NewInt(1), 511),
+			want:  KeyExchange{Type: kexDH, Size: 512, Logjam: true},
+		},
+		"custom 1024": {
+			prime: new(big.Int).Lsh(big.NewInt(1), 1023),
+			want:  KeyExchange{Type: kexDH, Size: 1024},
+		},
+	}
+
+	for name, tc := range tests {
+		kex := parseKeyExchange(dhServerKeyExchange(tc.prime), false)
+		if kex == nil || *kex != tc.want {
+			t.Errorf("%s: wrong key exchange, got: %+v, want: %+v", name, kex, tc.want)
+		}
+	}
+
+	kex := parseKeyExchange([]byte{3, 0, 29, 32}, true)
+	if kex == nil || kex.Group != "x25519" || kex.Size != 253 {
+		t.Errorf("wrong ecdh key exchange, got: %+v", kex)
+	}
+
+	if kex = parseKeyExchange(nil, false); kex != nil {
+		t.Errorf("empty key exchange should be nil, got: %+v", kex)
+	}
+}
@@ -0,0 +1,207 @@
+package ssl
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	logger "github.com/jsandas/gologger"
+)
+
+// BER tags used by the LDAP StartTLS extended operation
+const (
+	berInteger     = 0x02
+	berOctetString = 0x04
+	berEnumerated  = 0x0a
+	berSequence    = 0x30
+
+	// ldapExtendedRequest is [APPLICATION 23]
+	ldapExtendedRequest = 0x77
+	// ldapExtendedResponse is [APPLICATION 24]
+	ldapExtendedResponse = 0x78
+	// ldapRequestName is the [0] requestName of an ExtendedRequest
+	ldapRequestName = 0x80
+)
+
+const (
+	// ldapStartTLSOID is the request name of the StartTLS extended operation
+	ldapStartTLSOID = "1.3.6.1.4.1.1466.20037"
+	// ldapMessageID is the message id of the StartTLS request
+	ldapMessageID = 1
+	// ldapSuccess is the success result code
+	ldapSuccess = 0
+	// berMaxLength limits the length of elements read from the server
+	berMaxLength = 1 << 16
+)
+
+// berEncode returns a BER element with a definite length
+func berEncode(tag byte, value []byte) []byte {
+	l := len(value)
+	b := []byte{tag}
+
+	switch {
+	case l < 0x80:
+		b = append(b, byte(l))
+	case l <= 0xff:
+		b = append(b, 0x81, byte(l))
+	default:
+		b = append(b, 0x82, byte(l>>8), byte(l))
+	}
+
+	return append(b, value...)
+}
+
+// readBER reads a single BER element from r. Lengths take up to 4 bytes,
+// Active Directory always sends 4 byte lengths (0x84)
+func readBER(r io.Reader) (byte, []byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+
+	l := int(header[1])
+	if l&0x80 != 0 {
+		n := l & 0x7f
+		if n == 0 || n > 4 {
+			return 0, nil, fmt.Errorf("unsupported ber length: %d bytes", n)
+		}
+
+		lb := make([]byte, n)
+		if _, err := io.ReadFull(r, lb); err != nil {
+			return 0, nil, err
+		}
+
+		var ul uint32
+		for _, c := range lb {
+			ul = ul<<8 | uint32(c)
+		}
+		if ul > berMaxLength {
+			return 0, nil, fmt.Errorf("ber element too long: %d", ul)
+		}
+		l = int(ul)
+	}
+
+	value := make([]byte, l)
+	if _, err := io.ReadFull(r, value); err != nil {
+		return 0, nil, err
+	}
+
+	return header[0], value, nil
+}
+
+// parseBER returns the first BER element of b and the bytes after it
+func parseBER(b []byte) (tag byte, value []byte, rest []byte, err error) {
+	r := &byteSliceReader{b: b}
+	if tag, value, err = readBER(r); err != nil {
+		return 0, nil, nil, errors.New("malformed ber element")
+	}
+
+	return tag, value, r.b, nil
+}
+
+// byteSliceReader reads from b, keeping what is left unread
+type byteSliceReader struct {
+	b []byte
+}
+
+func (r *byteSliceReader) Read(p []byte) (int, error) {
+	if len(r.b) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, r.b)
+	r.b = r.b[n:]
+	return n, nil
+}
+
+// berInt returns the value of an INTEGER or ENUMERATED element
+func berInt(b []byte) int {
+	var n int
+	for i, c := range b {
+		if i == 0 && c&0x80 != 0 {
+			n = -1
+		}
+		n = n<<8 | int(c)
+	}
+	return n
+}
+
+// ldapStartTLSRequest returns the LDAPMessage of the StartTLS extended
+// request
+func ldapStartTLSRequest() []byte {
+	op := berEncode(ldapExtendedRequest, berEncode(ldapRequestName, []byte(ldapStartTLSOID)))
+	msg := append(berEncode(berInteger, []byte{ldapMessageID}), op...)
+
+	return berEncode(berSequence, msg)
+}
+
+// parseLDAPExtendedResponse returns the message id, result code and
+// diagnostic message of an ExtendedResponse LDAPMessage
+func parseLDAPExtendedResponse(b []byte) (id int, code int, diag string, err error) {
+	tag, value, rest, err := parseBER(b)
+	if err != nil || tag != berInteger {
+		return 0, 0, "", errors.New("malformed ldap message id")
+	}
+	id = berInt(value)
+
+	tag, op, _, err := parseBER(rest)
+	if err != nil || tag != ldapExtendedResponse {
+		return id, 0, "", fmt.Errorf("unexpected ldap operation: 0x%x", tag)
+	}
+
+	// resultCode, matchedDN and diagnosticMessage
+	tag, value, op, err = parseBER(op)
+	if err != nil || tag != berEnumerated {
+		return id, 0, "", errors.New("malformed ldap result code")
+	}
+	code = berInt(value)
+
+	if _, _, op, err = parseBER(op); err == nil {
+		if tag, value, _, err = parseBER(op); err == nil && tag == berOctetString {
+			diag = string(value)
+		}
+	}
+
+	return id, code, diag, nil
+}
+
+// ldapStartTLS sends the StartTLS extended request, the TLS handshake
+// follows a success result
+func ldapStartTLS(conn net.Conn) error {
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	defer conn.SetDeadline(time.Time{})
+
+	if _, err := conn.Write(ldapStartTLSRequest()); err != nil {
+		return err
+	}
+
+	// the response is read directly from conn, anything buffered after it
+	// would be injected into the TLS stream
+	tag, msg, err := readBER(conn)
+	if err != nil {
+		logger.Debugf("event_id=ldap_response_failed msg=\"%v\"", err)
+		return err
+	}
+	if tag != berSequence {
+		return fmt.Errorf("unexpected ldap message: 0x%x", tag)
+	}
+
+	id, code, diag, err := parseLDAPExtendedResponse(msg)
+	if err != nil {
+		logger.Debugf("event_id=ldap_response_failed msg=\"%v\"", err)
+		return err
+	}
+
+	// servers send unsolicited notifications with message id 0 before
+	// closing the connection
+	if code != ldapSuccess || id != ldapMessageID {
+		logger.Debugf("event_id=starttls_not_supported server=ldap id=%d code=%d msg=\"%s\"", id, code, diag)
+		if diag != "" {
+			return fmt.Errorf("%w: ldap result code %d (%s)", errStartTLSNotSupported, code, diag)
+		}
+		return fmt.Errorf("%w: ldap result code %d", errStartTLSNotSupported, code)
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package ssl
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"net"
+	"testing"
+)
+
+// ldapExtendedResponseMessage returns an ExtendedResponse LDAPMessage with
+// result code and diagnostic message diag
+func ldapExtendedResponseMessage(id byte, code byte, diag string) []byte {
+	result := berEncode(berEnumerated, []byte{code})
+	result = append(result, berEncode(berOctetString, nil)...)
+	result = append(result, berEncode(berOctetString, []byte(diag))...)
+
+	msg := berEncode(berInteger, []byte{id})
+	msg = append(msg, berEncode(ldapExtendedResponse, result)...)
+
+	return berEncode(berSequence, msg)
+}
+
+// ldapHandler returns a fake LDAP server answering the StartTLS request
+// with resp
+func ldapHandler(resp []byte) func(net.Conn, *tls.Config) {
+	return func(conn net.Conn, cfg *tls.Config) {
+		tag, req, err := readBER(conn)
+		if err != nil || tag != berSequence || !bytes.Contains(req, []byte(ldapStartTLSOID)) {
+			return
+		}
+
+		conn.Write(resp)
+		if bytes.Equal(resp, ldapExtendedResponseMessage(ldapMessageID, ldapSuccess, "")) {
+			tls.Server(conn, cfg).Handshake()
+		}
+	}
+}
+
+func TestBER(t *testing.T) {
+	for _, l := range []int{0, 0x7f, 0x80, 0xff, 0x100, 0x1234} {
+		value := bytes.Repeat([]byte{0x41}, l)
+
+		tag, got, rest, err := parseBER(append(berEncode(berOctetString, value), 0x01))
+		if err != nil || tag != berOctetString || !bytes.Equal(got, value) || !bytes.Equal(rest, []byte{0x01}) {
+			t.Errorf("length %d: wrong element, tag: 0x%x len: %d rest: %x err: %v", l, tag, len(got), rest, err)
+		}
+	}
+
+	if _, _, _, err := parseBER([]byte{berOctetString, 0x05, 0x41}); err == nil {
+		t.Errorf("truncated element should fail")
+	}
+	if _, _, err := readBER(bytes.NewReader([]byte{berOctetString, 0x84, 0x7f, 0xff, 0xff, 0xff})); err == nil {
+		t.Errorf("oversized length should fail")
+	}
+
+	// Active Directory sends 4 byte lengths
+	tag, got, err := readBER(bytes.NewReader([]byte{berSequence, 0x84, 0x00, 0x00, 0x00, 0x02, 0x41, 0x42}))
+	if err != nil || tag != berSequence || !bytes.Equal(got, []byte{0x41, 0x42}) {
+		t.Errorf("4 byte length: wrong element, tag: 0x%x value: %x err: %v", tag, got, err)
+	}
+	if _, _, err := readBER(bytes.NewReader([]byte{berOctetString, 0x85, 0, 0, 0, 0, 1, 0x41})); err == nil {
+		t.Errorf("5 byte length should fail")
+	}
+
+	if n := berInt([]byte{0x01, 0x00}); n != 256 {
+		t.Errorf("wrong integer, got: %d", n)
+	}
+	if n := berInt([]byte{0xff}); n != -1 {
+		t.Errorf("wrong negative integer, got: %d", n)
+	}
+}
+
+func TestLDAPStartTLSRequest(t *testing.T) {
+	want := append([]byte{0x30, 0x1d, 0x02, 0x01, 0x01, 0x77, 0x18, 0x80, 0x16}, ldapStartTLSOID...)
+	if got := ldapStartTLSRequest(); !bytes.Equal(got, want) {
+		t.Errorf("wrong request, got: %x want: %x", got, want)
+	}
+}
+
+func TestLDAPStartTLS(t *testing.T) {
+	var tests = []struct {
+		name string
+		resp []byte
+		err  error
+		msg  string
+	}{
+		{"success", ldapExtendedResponseMessage(ldapMessageID, ldapSuccess, ""), nil, ""},
+		{"protocol error", ldapExtendedResponseMessage(ldapMessageID, 2, "unsupported extended operation"), errStartTLSNotSupported, "starttls_not_supported: ldap result code 2 (unsupported extended operation)"},
+		{"unavailable", ldapExtendedResponseMessage(ldapMessageID, 52, ""), errStartTLSNotSupported, "starttls_not_supported: ldap result code 52"},
+		{"notice of disconnection", ldapExtendedResponseMessage(0, 2, ""), errStartTLSNotSupported, "starttls_not_supported: ldap result code 2"},
+	}
+
+	for _, test := range tests {
+		host, port := startServiceServer(t, ldapHandler(test.resp))
+
+		conn, err := net.Dial("tcp", net.JoinHostPort(host, port))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = ldapStartTLS(conn)
+		if !errors.Is(err, test.err) || (err != nil && err.Error() != test.msg) {
+			t.Errorf("%s: got %v, want %s", test.name, err, test.msg)
+		}
+
+		if err == nil {
+			client := tls.Client(conn, &tls.Config{InsecureSkipVerify: true})
+			if err := client.Handshake(); err != nil {
+				t.Errorf("%s: tls handshake failed after starttls: %v", test.name, err)
+			}
+		}
+		conn.Close()
+	}
+}
+
+func TestConnStateLDAP(t *testing.T) {
+	host, port := startServiceServer(t, ldapHandler(ldapExtendedResponseMessage(ldapMessageID, ldapSuccess, "")))
+
+	state, _ := ConnStateWithOptions(host, port, serviceOptions("ldap"))
+	if len(state.PeerCertificates) == 0 {
+		t.Errorf("no certificates received over ldap")
+	}
+}
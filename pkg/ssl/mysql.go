@@ -0,0 +1,163 @@
+package ssl
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	logger "github.com/jsandas/gologger"
+)
+
+// MySQL capability flags
+const (
+	mysqlClientLongPassword     = 0x00000001
+	mysqlClientProtocol41       = 0x00000200
+	mysqlClientSSL              = 0x00000800
+	mysqlClientSecureConnection = 0x00008000
+)
+
+const (
+	// mysqlProtocolVersion is the version of the initial handshake packet
+	mysqlProtocolVersion = 10
+	// mysqlErrPacket is the header of an error packet
+	mysqlErrPacket = 0xff
+	// mysqlMaxPacketSize is the max packet size sent in the SSLRequest
+	mysqlMaxPacketSize = 1<<24 - 1
+	// mysqlCharsetUTF8 is utf8_general_ci
+	mysqlCharsetUTF8 = 33
+	// mysqlMaxPacketLength limits the length of packets read from the
+	// server, the initial handshake is well below it
+	mysqlMaxPacketLength = 1 << 12
+)
+
+// mysqlHandshake is the initial handshake packet sent by MySQL and
+// MariaDB servers
+type mysqlHandshake struct {
+	version      string
+	capabilities uint32
+	charset      byte
+}
+
+// readMySQLPacket returns the payload and sequence id of a packet
+func readMySQLPacket(r io.Reader) ([]byte, byte, error) {
+	var header [4]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, 0, err
+	}
+
+	l := int(header[0]) | int(header[1])<<8 | int(header[2])<<16
+	if l > mysqlMaxPacketLength {
+		return nil, 0, fmt.Errorf("mysql packet too long: %d", l)
+	}
+
+	payload := make([]byte, l)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, 0, err
+	}
+
+	return payload, header[3], nil
+}
+
+// mysqlPacket returns payload as a packet with sequence id seq
+func mysqlPacket(seq byte, payload []byte) []byte {
+	l := len(payload)
+	return append([]byte{byte(l), byte(l >> 8), byte(l >> 16), seq}, payload...)
+}
+
+// parseMySQLHandshake parses an initial handshake packet
+func parseMySQLHandshake(b []byte) (*mysqlHandshake, error) {
+	if len(b) > 3 && b[0] == mysqlErrPacket {
+		return nil, fmt.Errorf("mysql error %d: %s", binary.LittleEndian.Uint16(b[1:3]), b[3:])
+	}
+	if len(b) == 0 || b[0] != mysqlProtocolVersion {
+		return nil, errors.New("unsupported mysql handshake")
+	}
+
+	end := bytes.IndexByte(b[1:], 0)
+	if end < 0 {
+		return nil, errors.New("malformed mysql handshake")
+	}
+
+	h := &mysqlHandshake{version: string(b[1 : 1+end])}
+
+	// thread id, auth-plugin-data-part-1 and filler
+	off := 1 + end + 1 + 4 + 8 + 1
+	if len(b) < off+2 {
+		return nil, errors.New("malformed mysql handshake")
+	}
+	b = b[off:]
+	h.capabilities = uint32(binary.LittleEndian.Uint16(b))
+
+	// character set, status flags and upper capability flags
+	if len(b) >= 7 {
+		h.charset = b[2]
+		h.capabilities |= uint32(binary.LittleEndian.Uint16(b[5:7])) << 16
+	}
+
+	return h, nil
+}
+
+// readMySQLHandshake reads the initial handshake packet from conn
+func readMySQLHandshake(conn net.Conn) (*mysqlHandshake, error) {
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	defer conn.SetDeadline(time.Time{})
+
+	payload, _, err := readMySQLPacket(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseMySQLHandshake(payload)
+}
+
+// mysqlStartTLS reads the initial handshake packet and answers with an
+// SSLRequest packet if the server supports TLS, the TLS handshake follows
+// right after
+func mysqlStartTLS(conn net.Conn) error {
+	h, err := readMySQLHandshake(conn)
+	if err != nil {
+		logger.Debugf("event_id=mysql_handshake_failed msg=\"%v\"", err)
+		return err
+	}
+
+	if h.capabilities&mysqlClientSSL == 0 {
+		logger.Debugf("event_id=starttls_not_supported server=mysql version=%s", h.version)
+		return errStartTLSNotSupported
+	}
+
+	charset := h.charset
+	if charset == 0 {
+		charset = mysqlCharsetUTF8
+	}
+
+	req := make([]byte, 32)
+	binary.LittleEndian.PutUint32(req, mysqlClientLongPassword|mysqlClientProtocol41|mysqlClientSSL|mysqlClientSecureConnection)
+	binary.LittleEndian.PutUint32(req[4:], mysqlMaxPacketSize)
+	req[8] = charset
+
+	_, err = conn.Write(mysqlPacket(1, req))
+	return err
+}
+
+// MySQLVersion returns the server version of the initial handshake packet
+func MySQLVersion(host string, port string) (string, error) {
+	var server = host + ":" + port
+
+	conn, err := net.DialTimeout("tcp", server, 3*time.Second)
+	if err != nil {
+		logger.Debugf("event_id=tcp_dial_failed server=%s msg\"%v\"", server, err)
+		return "", err
+	}
+	defer conn.Close()
+
+	h, err := readMySQLHandshake(conn)
+	if err != nil {
+		return "", err
+	}
+
+	return h.version, nil
+}
@@ -0,0 +1,136 @@
+package ssl
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/jsandas/etls"
+)
+
+const testMySQLVersion = "10.6.12-MariaDB-1:10.6.12+maria~ubu2004"
+
+// mysqlServerHandshake returns an initial handshake packet with the given
+// capabilities
+func mysqlServerHandshake(capabilities uint32) []byte {
+	b := []byte{mysqlProtocolVersion}
+	b = append(b, testMySQLVersion...)
+	b = append(b, 0)
+	b = append(b, 1, 0, 0, 0)
+	b = append(b, "12345678"...)
+	b = append(b, 0)
+	b = binary.LittleEndian.AppendUint16(b, uint16(capabilities))
+	b = append(b, 8, 2, 0)
+	b = binary.LittleEndian.AppendUint16(b, uint16(capabilities>>16))
+	b = append(b, 21)
+	b = append(b, make([]byte, 10)...)
+	b = append(b, "9abcdefghijkl"...)
+	b = append(b, 0)
+	b = append(b, "mysql_native_password"...)
+	b = append(b, 0)
+
+	return mysqlPacket(0, b)
+}
+
+// mysqlHandler returns a fake MySQL server that sends the initial
+// handshake packet and performs the TLS handshake after a valid SSLRequest
+func mysqlHandler(capabilities uint32) func(net.Conn, *tls.Config) {
+	return func(conn net.Conn, cfg *tls.Config) {
+		conn.Write(mysqlServerHandshake(capabilities))
+
+		req, seq, err := readMySQLPacket(conn)
+		if err != nil || seq != 1 || len(req) != 32 || binary.LittleEndian.Uint32(req)&mysqlClientSSL == 0 {
+			return
+		}
+
+		tls.Server(conn, cfg).Handshake()
+	}
+}
+
+func TestParseMySQLHandshake(t *testing.T) {
+	payload, seq, err := readMySQLPacket(bytes.NewReader(mysqlServerHandshake(0x81bfffff)))
+	if err != nil || seq != 0 {
+		t.Fatalf("unable to read packet, seq: %d err: %v", seq, err)
+	}
+
+	h, err := parseMySQLHandshake(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.version != testMySQLVersion || h.charset != 8 || h.capabilities != 0x81bfffff {
+		t.Errorf("wrong handshake, got: %+v", h)
+	}
+
+	_, err = parseMySQLHandshake(append([]byte{mysqlErrPacket, 0x6a, 0x04}, "Host is not allowed to connect"...))
+	if err == nil || err.Error() != "mysql error 1130: Host is not allowed to connect" {
+		t.Errorf("wrong error, got: %v", err)
+	}
+
+	if _, err := parseMySQLHandshake([]byte{mysqlProtocolVersion, 'x', 0, 1}); err == nil {
+		t.Errorf("truncated handshake should fail")
+	}
+}
+
+func TestReadMySQLPacketTooLong(t *testing.T) {
+	// a 16 MiB length is refused before the payload is allocated
+	_, _, err := readMySQLPacket(bytes.NewReader([]byte{0xff, 0xff, 0xff, 0}))
+	if err == nil || err.Error() != "mysql packet too long: 16777215" {
+		t.Errorf("wrong error, got: %v", err)
+	}
+}
+
+func TestMySQLStartTLS(t *testing.T) {
+	host, port := startServiceServer(t, mysqlHandler(mysqlClientProtocol41|mysqlClientSSL))
+
+	conn, err := net.Dial("tcp", net.JoinHostPort(host, port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if err := mysqlStartTLS(conn); err != nil {
+		t.Fatal(err)
+	}
+
+	client := tls.Client(conn, &tls.Config{InsecureSkipVerify: true})
+	if err := client.Handshake(); err != nil {
+		t.Errorf("tls handshake failed after ssl request: %v", err)
+	}
+
+	version, err := MySQLVersion(host, port)
+	if err != nil || version != testMySQLVersion {
+		t.Errorf("wrong version, got: %s err: %v", version, err)
+	}
+}
+
+func TestMySQLStartTLSNotSupported(t *testing.T) {
+	host, port := startServiceServer(t, mysqlHandler(mysqlClientProtocol41))
+
+	conn, err := net.Dial("tcp", net.JoinHostPort(host, port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if err := mysqlStartTLS(conn); !errors.Is(err, errStartTLSNotSupported) {
+		t.Errorf("expected %v, got: %v", errStartTLSNotSupported, err)
+	}
+}
+
+func TestConnStateMySQL(t *testing.T) {
+	host, port := startServiceServer(t, mysqlHandler(mysqlClientProtocol41|mysqlClientSSL))
+
+	opts := serviceOptions("mysql")
+
+	state, _ := ConnStateWithOptions(host, port, opts)
+	if len(state.PeerCertificates) == 0 {
+		t.Errorf("no certificates received over mysql")
+	}
+
+	if !serverDial(host, port, etls.VersionTLS12, nil, opts) {
+		t.Errorf("tls 1.2 should be supported over mysql")
+	}
+}
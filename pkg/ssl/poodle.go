@@ -0,0 +1,143 @@
+package ssl
+
+import (
+	"errors"
+	"io"
+	"maps"
+	"net"
+	"slices"
+
+	"github.com/jsandas/etls"
+	logger "github.com/jsandas/gologger"
+)
+
+// poodleRequest is sent as application data with malformed padding
+var poodleRequest = []byte("GET / HTTP/1.1\r\nHost: localhost\r\n\r\n")
+
+// POODLE checks if the server negotiates SSLv3 with a CBC cipher
+// (CVE-2014-3566)
+type POODLE struct {
+	Vulnerable string `json:"vulnerable"`
+}
+
+// Check offers SSLv3 with only block ciphers
+func (v *POODLE) Check(host string, port string, opts Options) error {
+	ciphers := blockCiphers(protocolCiphers(etls.VersionSSL30))
+
+	flight, err := rawHandshake(host, port, newClientHello(host, etls.VersionSSL30, ciphers), opts)
+	if flight != nil && flight.hello != nil {
+		if flight.hello.version == etls.VersionSSL30 {
+			logger.Debugf("event_id=poodle_sslv3_cbc cipher=%s", cipherSuites[flight.hello.cipher].name)
+			v.Vulnerable = vulnerable
+		} else {
+			v.Vulnerable = notVulnerable
+		}
+		return nil
+	}
+
+	if isRefusal(err) {
+		v.Vulnerable = notVulnerable
+		return nil
+	}
+
+	v.Vulnerable = testFailed
+	return err
+}
+
+// TLSPOODLE checks if the server ignores the contents of the CBC padding
+// in TLS records (CVE-2014-8730)
+type TLSPOODLE struct {
+	Vulnerable string `json:"vulnerable"`
+	// Protocol is the protocol the malformed record was sent with
+	Protocol string `json:"protocol,omitempty"`
+}
+
+// Check completes a handshake with a CBC cipher and sends an application
+// data record whose padding bytes do not match the padding length. A
+// server checking the padding answers with an alert and closes the
+// connection, a vulnerable server processes the request
+func (v *TLSPOODLE) Check(host string, port string, opts Options) error {
+	s, err := cbcHandshake(host, port, sessionCipherList(), opts)
+	if err != nil {
+		if errors.Is(err, errUnsupportedSession) || isRefusal(err) {
+			v.Vulnerable = notApplicable
+			return nil
+		}
+		v.Vulnerable = testFailed
+		return err
+	}
+	defer s.Close()
+
+	v.Protocol = protocolVersionMap[int(s.version)]
+
+	if err := s.writeRecord(recordTypeApplicationData, poodleRequest, true); err != nil {
+		v.Vulnerable = testFailed
+		return err
+	}
+
+	typ, _, _, err := readRecord(s.conn)
+	switch {
+	case err == nil && typ == recordTypeApplicationData:
+		logger.Debugf("event_id=tls_poodle_padding_ignored proto=%s", v.Protocol)
+		v.Vulnerable = vulnerable
+	case err == nil && typ == recordTypeAlert, isRefusal(err):
+		v.Vulnerable = notVulnerable
+	default:
+		v.Vulnerable = testFailed
+		return err
+	}
+
+	return nil
+}
+
+// cbcHandshake completes a handshake with the highest protocol up to
+// TLS 1.2 that the server accepts one of ciphers with
+func cbcHandshake(host string, port string, ciphers []uint16, opts Options) (*cbcSession, error) {
+	var err error = errUnsupportedSession
+
+	for _, p := range []int{etls.VersionTLS12, etls.VersionTLS11, etls.VersionTLS10} {
+		var s *cbcSession
+		s, err = startSession(host, port, p, ciphers, opts)
+		if err != nil {
+			logger.Debugf("event_id=cbc_session_failed proto=%s msg=\"%v\"", protocolVersionMap[p], err)
+			continue
+		}
+
+		if err = s.handshake(); err != nil {
+			logger.Debugf("event_id=cbc_handshake_failed proto=%s msg=\"%v\"", protocolVersionMap[p], err)
+			s.Close()
+			continue
+		}
+
+		return s, nil
+	}
+
+	return nil, err
+}
+
+// sessionCipherList returns the ciphers supported by cbcSession with the
+// ECDHE suites first
+func sessionCipherList() []uint16 {
+	ciphers := slices.Sorted(maps.Keys(sessionCiphers))
+	slices.Reverse(ciphers)
+	return ciphers
+}
+
+// isRefusal reports if err is the server rejecting the connection with an
+// alert or by closing it
+func isRefusal(err error) bool {
+	var alert alertError
+	var opErr *net.OpError
+
+	switch {
+	case err == nil:
+		return false
+	case errors.As(err, &alert), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return true
+	case errors.As(err, &opErr):
+		// connection reset, but not a failed dial or a timeout
+		return opErr.Op != "dial" && !opErr.Timeout()
+	}
+
+	return false
+}
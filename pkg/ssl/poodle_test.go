@@ -0,0 +1,89 @@
+package ssl
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/jsandas/etls"
+)
+
+func TestPOODLE(t *testing.T) {
+	tests := []struct {
+		version uint16
+		want    string
+	}{
+		{uint16(etls.VersionSSL30), vulnerable},
+		{uint16(etls.VersionTLS12), notVulnerable},
+	}
+
+	for _, tt := range tests {
+		srv := &fakeFeatureServer{version: tt.version}
+		host, port := srv.start(t)
+
+		var v POODLE
+		v.Check(host, port, DefaultOptions())
+		if v.Vulnerable != tt.want {
+			t.Errorf("wrong result for %#04x, got: %s, want: %s", tt.version, v.Vulnerable, tt.want)
+		}
+	}
+}
+
+func TestPOODLENoServer(t *testing.T) {
+	var v POODLE
+	if err := v.Check("127.0.0.1", "1", DefaultOptions()); err == nil || v.Vulnerable != testFailed {
+		t.Errorf("wrong result, got: %s, %v, want: %s", v.Vulnerable, err, testFailed)
+	}
+}
+
+func TestTLSPOODLEGoServer(t *testing.T) {
+	host, port := startFeatureServer(t, &tls.Config{
+		Certificates: []tls.Certificate{featureCertificate(t, false)},
+		CipherSuites: []uint16{etls.TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA},
+		MaxVersion:   uint16(etls.VersionTLS12),
+	})
+
+	var v TLSPOODLE
+	if err := v.Check(host, port, DefaultOptions()); err != nil {
+		t.Fatal(err)
+	}
+	if v.Vulnerable != notVulnerable || v.Protocol != "TLSv1.2" {
+		t.Errorf("wrong result, got: %+v", v)
+	}
+}
+
+func TestTLSPOODLEFakeServer(t *testing.T) {
+	tests := []struct {
+		checkPadding bool
+		want         string
+	}{
+		{true, notVulnerable},
+		{false, vulnerable},
+	}
+
+	for _, tt := range tests {
+		srv := newFakeSessionServer(t)
+		srv.checkPadding = tt.checkPadding
+		host, port := srv.start(t)
+
+		var v TLSPOODLE
+		if err := v.Check(host, port, DefaultOptions()); err != nil {
+			t.Fatal(err)
+		}
+		if v.Vulnerable != tt.want {
+			t.Errorf("wrong result with padding check %v, got: %s, want: %s", tt.checkPadding, v.Vulnerable, tt.want)
+		}
+	}
+}
+
+func TestTLSPOODLENoCBC(t *testing.T) {
+	host, port := startFeatureServer(t, &tls.Config{
+		Certificates: []tls.Certificate{featureCertificate(t, false)},
+		MinVersion:   uint16(etls.VersionTLS13),
+	})
+
+	var v TLSPOODLE
+	v.Check(host, port, DefaultOptions())
+	if v.Vulnerable != notApplicable {
+		t.Errorf("wrong result, got: %s, want: %s", v.Vulnerable, notApplicable)
+	}
+}
@@ -0,0 +1,85 @@
+package ssl
+
+import (
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/jsandas/etls"
+	logger "github.com/jsandas/gologger"
+)
+
+// postgresSSLRequestCode is the request code of the SSLRequest message
+const postgresSSLRequestCode = 80877103
+
+// PostgresALPN is the ALPN protocol required by direct TLS connections
+// (Postgres 17+)
+const PostgresALPN = "postgresql"
+
+// errPostgresDirectTLSOnly is returned when the server closes the
+// connection or answers the SSLRequest with a TLS record, like TLS
+// terminating proxies that only accept direct TLS
+var errPostgresDirectTLSOnly = errors.New("postgres_direct_tls_only")
+
+// postgresStartTLS sends an SSLRequest and reads the single byte answer,
+// 'S' means the TLS handshake follows
+func postgresStartTLS(conn net.Conn) error {
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	defer conn.SetDeadline(time.Time{})
+
+	req := make([]byte, 8)
+	binary.BigEndian.PutUint32(req, 8)
+	binary.BigEndian.PutUint32(req[4:], postgresSSLRequestCode)
+
+	if _, err := conn.Write(req); err != nil {
+		return err
+	}
+
+	// the answer is read directly from conn, anything buffered after it
+	// would be injected into the TLS stream
+	resp := make([]byte, 1)
+	if _, err := io.ReadFull(conn, resp); err != nil {
+		logger.Debugf("event_id=ssl_request_failed server=postgres msg=\"%v\"", err)
+		if errors.Is(err, io.EOF) {
+			return errPostgresDirectTLSOnly
+		}
+		return err
+	}
+
+	switch resp[0] {
+	case 'S':
+		return nil
+	case 'N':
+		logger.Debugf("event_id=starttls_not_supported server=postgres")
+		return errStartTLSNotSupported
+	case recordTypeAlert, recordTypeHandshake:
+		logger.Debugf("event_id=ssl_request_tls_record server=postgres")
+		return errPostgresDirectTLSOnly
+	default:
+		// servers older than 7.4 answer with an ErrorResponse
+		return fmt.Errorf("unexpected ssl request response: %q", resp[0])
+	}
+}
+
+// postgresDirectTLS reports if the server accepts direct TLS connections
+// of protocol p offering the supported ciphers, servers without a protocol
+// below TLS 1.3 are tested with a TLS 1.3 handshake
+func postgresDirectTLS(host string, port string, p int, ciphers []uint16, opts Options) bool {
+	opts.StartTLS.PostgresDirectTLS = true
+
+	cfg := &tls.Config{
+		ServerName:         host,
+		InsecureSkipVerify: true,
+		MinVersion:         uint16(etls.VersionTLS13),
+	}
+	if p != 0 && len(ciphers) > 0 {
+		cfg = probeConfig(host, p, ciphers)
+	}
+
+	state, _, err := recordedHandshake(host, port, cfg, opts)
+	return err == nil && state.NegotiatedProtocol == PostgresALPN
+}
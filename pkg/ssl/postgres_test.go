@@ -0,0 +1,201 @@
+package ssl
+
+import (
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"slices"
+	"testing"
+
+	"github.com/jsandas/etls"
+)
+
+// postgresHandler returns a fake Postgres server answering SSLRequests
+// with resp. Like a direct TLS only proxy it answers with an alert record
+// when resp is recordTypeAlert and closes the connection when resp is 0.
+// Connections starting with a TLS record are handled as direct TLS with
+// the postgresql ALPN protocol
+func postgresHandler(resp byte) func(net.Conn, *tls.Config) {
+	return func(conn net.Conn, cfg *tls.Config) {
+		cfg = cfg.Clone()
+		cfg.NextProtos = []string{PostgresALPN}
+
+		req := make([]byte, 8)
+		if _, err := io.ReadFull(conn, req); err != nil {
+			return
+		}
+
+		if req[0] == recordTypeHandshake {
+			// direct tls handshakes without the postgresql protocol are refused
+			cfg.GetConfigForClient = func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+				if !slices.Contains(hello.SupportedProtos, PostgresALPN) {
+					return nil, errors.New("missing postgresql alpn protocol")
+				}
+				return nil, nil
+			}
+			tls.Server(&prefixConn{Conn: conn, prefix: req}, cfg).Handshake()
+			return
+		}
+
+		if binary.BigEndian.Uint32(req) != 8 || binary.BigEndian.Uint32(req[4:]) != postgresSSLRequestCode {
+			return
+		}
+
+		switch resp {
+		case 0:
+		case recordTypeAlert:
+			// unexpected_message
+			conn.Write(record(recordTypeAlert, etls.VersionTLS12, []byte{2, 10}))
+		case 'S':
+			conn.Write([]byte{resp})
+			tls.Server(conn, cfg).Handshake()
+		default:
+			conn.Write([]byte{resp})
+		}
+	}
+}
+
+// prefixConn returns prefix before reading from Conn
+type prefixConn struct {
+	net.Conn
+	prefix []byte
+}
+
+func (c *prefixConn) Read(b []byte) (int, error) {
+	if len(c.prefix) > 0 {
+		n := copy(b, c.prefix)
+		c.prefix = c.prefix[n:]
+		return n, nil
+	}
+	return c.Conn.Read(b)
+}
+
+func TestPostgresStartTLS(t *testing.T) {
+	var tests = []struct {
+		resp byte
+		err  error
+	}{
+		{'S', nil},
+		{'N', errStartTLSNotSupported},
+		{recordTypeAlert, errPostgresDirectTLSOnly},
+		{0, errPostgresDirectTLSOnly},
+	}
+
+	for _, test := range tests {
+		host, port := startServiceServer(t, postgresHandler(test.resp))
+
+		conn, err := net.Dial("tcp", net.JoinHostPort(host, port))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = postgresStartTLS(conn)
+		if !errors.Is(err, test.err) {
+			t.Errorf("response %q: expected %v, got: %v", test.resp, test.err, err)
+		}
+
+		if err == nil {
+			client := tls.Client(conn, &tls.Config{InsecureSkipVerify: true})
+			if err := client.Handshake(); err != nil {
+				t.Errorf("tls handshake failed after ssl request: %v", err)
+			}
+		}
+		conn.Close()
+	}
+
+	host, port := startServiceServer(t, postgresHandler('E'))
+	conn, err := net.Dial("tcp", net.JoinHostPort(host, port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if err := postgresStartTLS(conn); err == nil {
+		t.Errorf("error response should fail")
+	}
+}
+
+func TestPostgresDirectTLS(t *testing.T) {
+	host, port := startServiceServer(t, postgresHandler('S'))
+	if !postgresDirectTLS(host, port, 0, nil, DefaultOptions()) {
+		t.Errorf("direct tls should be supported")
+	}
+	ciphers := cipherIDList(featureCiphers["TLSv1.2"])
+	if !postgresDirectTLS(host, port, etls.VersionTLS12, ciphers, DefaultOptions()) {
+		t.Errorf("direct tls should be supported with the supported ciphers")
+	}
+
+	// a server without the postgresql alpn protocol
+	host, port = startFeatureServer(t, &tls.Config{Certificates: []tls.Certificate{featureCertificate(t, false)}})
+	if postgresDirectTLS(host, port, 0, nil, DefaultOptions()) {
+		t.Errorf("direct tls should not be supported")
+	}
+}
+
+func TestConnStatePostgres(t *testing.T) {
+	host, port := startServiceServer(t, postgresHandler('S'))
+
+	opts := serviceOptions("postgres")
+
+	state, _ := ConnStateWithOptions(host, port, opts)
+	if len(state.PeerCertificates) == 0 {
+		t.Errorf("no certificates received over postgres")
+	}
+
+	if !serverDial(host, port, etls.VersionTLS12, nil, opts) {
+		t.Errorf("tls 1.2 should be supported over postgres")
+	}
+
+	f := CheckFeatures(host, port, nil, opts)
+	if !f.PostgresDirectTLS {
+		t.Errorf("direct tls should be reported")
+	}
+}
+
+func TestConnStatePostgresDirectOnly(t *testing.T) {
+	// the SSLRequest is answered with a TLS alert, the certificate is read
+	// with direct tls
+	host, port := startServiceServer(t, postgresHandler(recordTypeAlert))
+
+	opts := serviceOptions("postgres")
+
+	state, tlsv := ConnStateWithOptions(host, port, opts)
+	if len(state.PeerCertificates) == 0 || tlsv == 0 {
+		t.Errorf("no certificates received over direct tls")
+	}
+	if state.NegotiatedProtocol != PostgresALPN {
+		t.Errorf("wrong alpn protocol, got: %s", state.NegotiatedProtocol)
+	}
+
+	if serverDial(host, port, etls.VersionTLS12, nil, opts) {
+		t.Errorf("the ssl request should fail")
+	}
+
+	// the probes connect with direct tls and the postgresql protocol
+	opts.StartTLS.PostgresDirectTLS = true
+	if !serverDial(host, port, etls.VersionTLS12, nil, opts) {
+		t.Errorf("tls 1.2 should be supported with direct tls")
+	}
+
+	hello := newClientHello(host, etls.VersionTLS12, protocolCiphers(etls.VersionTLS12))
+	hello.setGroups(kexGroups(etls.VersionTLS12))
+	if flight, err := rawHandshake(host, port, hello, opts); err != nil || flight.hello == nil {
+		t.Errorf("raw handshake with direct tls failed: %v", err)
+	}
+
+	supported := CheckWithOptions(host, port, "ECDSA", opts)
+	if _, ok := supported["TLSv1.2"]; !ok {
+		t.Errorf("no tls 1.2 ciphers found with direct tls, got: %v", supported)
+	}
+}
+
+func TestConnStatePostgresNotSupported(t *testing.T) {
+	// servers refusing the SSLRequest do not offer tls at all
+	host, port := startServiceServer(t, postgresHandler('N'))
+
+	state, tlsv := ConnStateWithOptions(host, port, serviceOptions("postgres"))
+	if len(state.PeerCertificates) != 0 || tlsv != 0 {
+		t.Errorf("direct tls should not be tried, got: %d", tlsv)
+	}
+}
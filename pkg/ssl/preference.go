@@ -0,0 +1,144 @@
+package ssl
+
+import (
+	"slices"
+	"sync"
+
+	logger "github.com/jsandas/gologger"
+)
+
+// cipher preference results
+const (
+	preferServer  = "server"
+	preferClient  = "client"
+	notApplicable = "n/a"
+)
+
+// CipherPreference describes how a server selects a cipher for a protocol
+type CipherPreference struct {
+	// Order is "server" when the server enforces its own order, "client"
+	// when it honours the order offered by the client and "n/a" when
+	// fewer than two ciphers are supported
+	Order string `json:"order"`
+	// Ciphers is the server's preferred order, most preferred first.
+	// It is only populated when the server enforces its own order
+	Ciphers []string `json:"ciphers,omitempty"`
+	// Incomplete is true when the server refused a handshake or picked a
+	// cipher that was not offered before its whole order was known.
+	// Ciphers then only holds the most preferred ciphers found
+	Incomplete bool `json:"incomplete,omitempty"`
+}
+
+// CipherOrder determines the cipher preference of the server for each
+// protocol found by Check
+func CipherOrder(host string, port string, supported map[string][]string, opts Options) map[string]CipherPreference {
+	var mutex = &sync.Mutex{}
+	var jobs []func()
+	preference := make(map[string]CipherPreference)
+
+	for pname, names := range supported {
+		p, ok := protocolVersion(pname)
+		if !ok {
+			continue
+		}
+
+		ciphers := cipherIDList(names)
+
+		jobs = append(jobs, func() {
+			pref := getCipherPreference(host, port, p, ciphers, opts)
+			mutex.Lock()
+			preference[pname] = pref
+			mutex.Unlock()
+		})
+	}
+
+	runPool(opts.workers(), jobs)
+
+	logger.Debugf("event_id=cipher_preference results=%v", preference)
+	return preference
+}
+
+// getCipherPreference offers the supported ciphers in opposite orders to
+// see if the server's pick follows the client
+func getCipherPreference(host string, port string, protocol int, ciphers []uint16, opts Options) CipherPreference {
+	var pref = CipherPreference{Order: notApplicable}
+
+	if len(ciphers) < 2 {
+		return pref
+	}
+
+	forward := slices.Clone(ciphers)
+	reverse := slices.Clone(ciphers)
+	slices.Reverse(reverse)
+
+	first, ok := serverDialCipher(host, port, protocol, forward, opts)
+	if !ok {
+		return pref
+	}
+	second, ok := serverDialCipher(host, port, protocol, reverse, opts)
+	if !ok {
+		return pref
+	}
+
+	if first == forward[0] && second == reverse[0] {
+		pref.Order = preferClient
+		return pref
+	}
+
+	order, complete := serverCipherOrder(host, port, protocol, forward, opts)
+	pref.Order = preferServer
+	pref.Ciphers = cipherStrList(order)
+	pref.Incomplete = !complete
+
+	return pref
+}
+
+// serverCipherOrder repeatedly offers the remaining ciphers and records
+// the server's pick to build its preferred order. The order is not
+// complete when a handshake fails or the server picks a cipher that was
+// not offered, only the ciphers picked until then are returned
+func serverCipherOrder(host string, port string, protocol int, ciphers []uint16, opts Options) ([]uint16, bool) {
+	var order []uint16
+
+	remaining := slices.Clone(ciphers)
+
+	for len(remaining) > 1 {
+		picked, ok := serverDialCipher(host, port, protocol, remaining, opts)
+		if !ok {
+			logger.Debugf("event_id=cipher_order_incomplete proto=%s remaining=%d", protocolVersionMap[protocol], len(remaining))
+			return order, false
+		}
+
+		i := slices.Index(remaining, picked)
+		if i < 0 {
+			logger.Debugf("event_id=unexpected_cipher proto=%s cipher=%#04x", protocolVersionMap[protocol], picked)
+			return order, false
+		}
+
+		order = append(order, picked)
+		remaining = slices.Delete(remaining, i, i+1)
+	}
+
+	return append(order, remaining...), true
+}
+
+// protocolVersion returns the version number for a protocol name
+func protocolVersion(name string) (int, bool) {
+	for p, n := range protocolVersionMap {
+		if n == name {
+			return p, true
+		}
+	}
+	return 0, false
+}
+
+// cipherIDList converts cipher names to code points
+func cipherIDList(names []string) []uint16 {
+	var ids []uint16
+	for _, name := range names {
+		if id, ok := cipherID(name); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,101 @@
+package ssl
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jsandas/etls"
+)
+
+var preferenceCiphers = []uint16{
+	etls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA,
+	etls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	etls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+}
+
+func TestCipherOrderServer(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Write([]byte("Hello"))
+	}))
+	server.TLS = &tls.Config{
+		MinVersion:   uint16(etls.VersionTLS12),
+		MaxVersion:   uint16(etls.VersionTLS12),
+		CipherSuites: preferenceCiphers,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	host, port, _ := net.SplitHostPort(strings.Replace(server.URL, "https://", "", -1))
+
+	supported := map[string][]string{"TLSv1.2": cipherStrList(preferenceCiphers)}
+	pref := CipherOrder(host, port, supported, DefaultOptions())
+
+	p := pref["TLSv1.2"]
+	if p.Order != preferServer {
+		t.Fatalf("wrong cipher order, got: %s, want: %s", p.Order, preferServer)
+	}
+
+	if len(p.Ciphers) != 3 {
+		t.Fatalf("wrong cipher count, got: %v, want: %d", p.Ciphers, 3)
+	}
+
+	// AEAD ciphers are preferred over CBC by crypto/tls
+	if p.Ciphers[2] != "TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA" {
+		t.Errorf("CBC cipher should be least preferred, got: %v", p.Ciphers)
+	}
+}
+
+func TestCipherOrderSingleCipher(t *testing.T) {
+	pref := getCipherPreference("127.0.0.1", "1", etls.VersionTLS12, []uint16{etls.TLS_RSA_WITH_AES_128_CBC_SHA}, DefaultOptions())
+
+	if pref.Order != notApplicable {
+		t.Errorf("wrong cipher order, got: %s, want: %s", pref.Order, notApplicable)
+	}
+}
+
+// refusingListener closes every connection after the first accept ones
+type refusingListener struct {
+	net.Listener
+	accept int32
+}
+
+func (l *refusingListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil || atomic.AddInt32(&l.accept, -1) >= 0 {
+			return conn, err
+		}
+		conn.Close()
+	}
+}
+
+func TestCipherOrderIncomplete(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Write([]byte("Hello"))
+	}))
+	server.TLS = &tls.Config{
+		MinVersion:   uint16(etls.VersionTLS12),
+		MaxVersion:   uint16(etls.VersionTLS12),
+		CipherSuites: preferenceCiphers,
+	}
+	// the two preference probes and the first pick of the order
+	server.Listener = &refusingListener{Listener: server.Listener, accept: 3}
+	server.StartTLS()
+	defer server.Close()
+
+	host, port, _ := net.SplitHostPort(strings.Replace(server.URL, "https://", "", -1))
+
+	p := getCipherPreference(host, port, etls.VersionTLS12, preferenceCiphers, DefaultOptions())
+
+	if p.Order != preferServer || !p.Incomplete {
+		t.Fatalf("order should be incomplete, got: %+v", p)
+	}
+	if len(p.Ciphers) != 1 {
+		t.Errorf("only the first pick should be reported, got: %v", p.Ciphers)
+	}
+}
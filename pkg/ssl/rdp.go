@@ -0,0 +1,171 @@
+package ssl
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	logger "github.com/jsandas/gologger"
+)
+
+// RDP security protocols of RDP_NEG_REQ and RDP_NEG_RSP
+const (
+	rdpProtocolRDP    = 0x00000000
+	rdpProtocolSSL    = 0x00000001
+	rdpProtocolHybrid = 0x00000002
+)
+
+const (
+	// tpktVersion is the version of the TPKT header
+	tpktVersion = 3
+	// x224ConnectionRequest and x224ConnectionConfirm are the X.224 TPDU
+	// codes
+	x224ConnectionRequest = 0xe0
+	x224ConnectionConfirm = 0xd0
+
+	// RDP negotiation structure types
+	rdpNegReq     = 0x01
+	rdpNegRsp     = 0x02
+	rdpNegFailure = 0x03
+	rdpNegLength  = 8
+
+	// rdpSSLNotAllowedByServer is the RDP_NEG_FAILURE code of servers
+	// configured for standard RDP security only
+	rdpSSLNotAllowedByServer = 0x00000002
+)
+
+// RDP security protocols reported by CheckFeatures
+const (
+	RDPSecurityStandard = "standard"
+	RDPSecurityTLS      = "tls"
+	RDPSecurityCredSSP  = "credssp"
+)
+
+// rdpSecurityNames maps the negotiated protocol to the reported name
+var rdpSecurityNames = map[uint32]string{
+	rdpProtocolRDP:    RDPSecurityStandard,
+	rdpProtocolSSL:    RDPSecurityTLS,
+	rdpProtocolHybrid: RDPSecurityCredSSP,
+}
+
+// rdpConnectionRequest returns the TPKT and X.224 Connection Request with
+// an RDP_NEG_REQ asking for TLS or CredSSP
+func rdpConnectionRequest() []byte {
+	neg := []byte{rdpNegReq, 0, rdpNegLength, 0}
+	neg = binary.LittleEndian.AppendUint32(neg, rdpProtocolSSL|rdpProtocolHybrid)
+
+	// length indicator, code, dst-ref, src-ref and class
+	x224 := []byte{byte(6 + len(neg)), x224ConnectionRequest, 0, 0, 0, 0, 0}
+	x224 = append(x224, neg...)
+
+	tpkt := []byte{tpktVersion, 0}
+	tpkt = binary.BigEndian.AppendUint16(tpkt, uint16(4+len(x224)))
+
+	return append(tpkt, x224...)
+}
+
+// readTPKT returns the payload of a TPKT packet
+func readTPKT(r io.Reader) ([]byte, error) {
+	var header [4]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+
+	l := int(binary.BigEndian.Uint16(header[2:]))
+	if header[0] != tpktVersion || l < 4 {
+		return nil, errors.New("malformed tpkt header")
+	}
+
+	payload := make([]byte, l-4)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
+
+// parseRDPConnectionConfirm returns the security protocol selected in the
+// X.224 Connection Confirm
+func parseRDPConnectionConfirm(b []byte) (uint32, error) {
+	if len(b) < 7 || b[1]&0xf0 != x224ConnectionConfirm {
+		return 0, errors.New("malformed x.224 connection confirm")
+	}
+
+	// servers only supporting standard RDP security do not send any
+	// negotiation data
+	neg := b[7:]
+	if len(neg) < rdpNegLength {
+		return rdpProtocolRDP, nil
+	}
+
+	code := binary.LittleEndian.Uint32(neg[4:8])
+	switch neg[0] {
+	case rdpNegRsp:
+		return code, nil
+	case rdpNegFailure:
+		if code == rdpSSLNotAllowedByServer {
+			return rdpProtocolRDP, nil
+		}
+		return 0, fmt.Errorf("%w: rdp negotiation failure code %d", errStartTLSNotSupported, code)
+	default:
+		return 0, fmt.Errorf("unexpected rdp negotiation type: %d", neg[0])
+	}
+}
+
+// rdpNegotiate sends the X.224 Connection Request and returns the security
+// protocol selected by the server
+func rdpNegotiate(conn net.Conn) (uint32, error) {
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	defer conn.SetDeadline(time.Time{})
+
+	if _, err := conn.Write(rdpConnectionRequest()); err != nil {
+		return 0, err
+	}
+
+	resp, err := readTPKT(conn)
+	if err != nil {
+		logger.Debugf("event_id=rdp_connection_confirm_failed msg=\"%v\"", err)
+		return 0, err
+	}
+
+	return parseRDPConnectionConfirm(resp)
+}
+
+// rdpStartTLS negotiates TLS or CredSSP, both start with a TLS handshake
+func rdpStartTLS(conn net.Conn) error {
+	protocol, err := rdpNegotiate(conn)
+	if err != nil {
+		logger.Debugf("event_id=rdp_negotiation_failed msg=\"%v\"", err)
+		return err
+	}
+
+	if protocol == rdpProtocolRDP {
+		logger.Debugf("event_id=starttls_not_supported server=rdp protocol=%s", RDPSecurityStandard)
+		return errStartTLSNotSupported
+	}
+
+	return nil
+}
+
+// RDPSecurity returns the security protocol negotiated by the RDP server
+func RDPSecurity(host string, port string, opts Options) (string, error) {
+	conn, err := opts.dial(host, port, 3*time.Second)
+	if err != nil {
+		logger.Debugf("event_id=tcp_dial_failed server=%s:%s msg\"%v\"", host, port, err)
+		return "", err
+	}
+	defer conn.Close()
+
+	protocol, err := rdpNegotiate(conn)
+	if err != nil {
+		return "", err
+	}
+
+	if name, ok := rdpSecurityNames[protocol]; ok {
+		return name, nil
+	}
+	return fmt.Sprintf("unknown (0x%x)", protocol), nil
+}
@@ -0,0 +1,121 @@
+package ssl
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"net"
+	"testing"
+)
+
+// rdpConnectionConfirm returns an X.224 Connection Confirm with a
+// negotiation structure of negType, or none when negType is 0
+func rdpConnectionConfirm(negType byte, code uint32) []byte {
+	var neg []byte
+	if negType != 0 {
+		neg = []byte{negType, 0, rdpNegLength, 0}
+		neg = binary.LittleEndian.AppendUint32(neg, code)
+	}
+
+	x224 := append([]byte{byte(6 + len(neg)), x224ConnectionConfirm, 0, 0, 0x12, 0x34, 0}, neg...)
+
+	tpkt := []byte{tpktVersion, 0}
+	tpkt = binary.BigEndian.AppendUint16(tpkt, uint16(4+len(x224)))
+
+	return append(tpkt, x224...)
+}
+
+// rdpHandler returns a fake RDP server answering the Connection Request
+// with resp, the TLS handshake follows when TLS or CredSSP is selected
+func rdpHandler(resp []byte) func(net.Conn, *tls.Config) {
+	handshake := bytes.Equal(resp, rdpConnectionConfirm(rdpNegRsp, rdpProtocolSSL)) ||
+		bytes.Equal(resp, rdpConnectionConfirm(rdpNegRsp, rdpProtocolHybrid))
+
+	return func(conn net.Conn, cfg *tls.Config) {
+		req, err := readTPKT(conn)
+		if err != nil || len(req) < 2 || req[1] != x224ConnectionRequest {
+			return
+		}
+
+		conn.Write(resp)
+		if handshake {
+			tls.Server(conn, cfg).Handshake()
+		}
+	}
+}
+
+func TestRDPConnectionRequest(t *testing.T) {
+	want := []byte{0x03, 0x00, 0x00, 0x13, 0x0e, 0xe0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x08, 0x00, 0x03, 0x00, 0x00, 0x00}
+	if got := rdpConnectionRequest(); !bytes.Equal(got, want) {
+		t.Errorf("wrong request, got: %x want: %x", got, want)
+	}
+}
+
+func TestRDPStartTLS(t *testing.T) {
+	var tests = []struct {
+		name     string
+		resp     []byte
+		err      error
+		security string
+	}{
+		{"tls", rdpConnectionConfirm(rdpNegRsp, rdpProtocolSSL), nil, RDPSecurityTLS},
+		{"credssp", rdpConnectionConfirm(rdpNegRsp, rdpProtocolHybrid), nil, RDPSecurityCredSSP},
+		{"standard", rdpConnectionConfirm(rdpNegRsp, rdpProtocolRDP), errStartTLSNotSupported, RDPSecurityStandard},
+		{"no negotiation", rdpConnectionConfirm(0, 0), errStartTLSNotSupported, RDPSecurityStandard},
+		{"ssl not allowed", rdpConnectionConfirm(rdpNegFailure, rdpSSLNotAllowedByServer), errStartTLSNotSupported, RDPSecurityStandard},
+		{"hybrid required", rdpConnectionConfirm(rdpNegFailure, 5), errStartTLSNotSupported, ""},
+	}
+
+	for _, test := range tests {
+		host, port := startServiceServer(t, rdpHandler(test.resp))
+
+		conn, err := net.Dial("tcp", net.JoinHostPort(host, port))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = rdpStartTLS(conn)
+		if !errors.Is(err, test.err) {
+			t.Errorf("%s: expected %v, got: %v", test.name, test.err, err)
+		}
+
+		if err == nil {
+			client := tls.Client(conn, &tls.Config{InsecureSkipVerify: true})
+			if err := client.Handshake(); err != nil {
+				t.Errorf("%s: tls handshake failed after negotiation: %v", test.name, err)
+			}
+		}
+		conn.Close()
+
+		if security, _ := RDPSecurity(host, port, DefaultOptions()); security != test.security {
+			t.Errorf("%s: wrong security, got: %s want: %s", test.name, security, test.security)
+		}
+	}
+}
+
+func TestParseRDPConnectionConfirm(t *testing.T) {
+	if _, err := parseRDPConnectionConfirm([]byte{0x02, 0xf0, 0x80}); err == nil {
+		t.Errorf("data tpdu should fail")
+	}
+
+	cc := rdpConnectionConfirm(0x07, 0)[4:]
+	if _, err := parseRDPConnectionConfirm(cc); err == nil {
+		t.Errorf("unknown negotiation type should fail")
+	}
+}
+
+func TestConnStateRDP(t *testing.T) {
+	host, port := startServiceServer(t, rdpHandler(rdpConnectionConfirm(rdpNegRsp, rdpProtocolHybrid)))
+	opts := serviceOptions("rdp")
+
+	state, _ := ConnStateWithOptions(host, port, opts)
+	if len(state.PeerCertificates) == 0 {
+		t.Errorf("no certificates received over rdp")
+	}
+
+	f := CheckFeatures(host, port, nil, opts)
+	if f.RDPSecurity != RDPSecurityCredSSP {
+		t.Errorf("wrong security, got: %s", f.RDPSecurity)
+	}
+}
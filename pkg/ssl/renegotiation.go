@@ -0,0 +1,147 @@
+package ssl
+
+import (
+	"errors"
+	"net"
+	"time"
+
+	"github.com/jsandas/etls"
+	logger "github.com/jsandas/gologger"
+)
+
+// noCBCDetail explains why client-initiated renegotiation was not tested
+const noCBCDetail = "client-initiated renegotiation is only tested with AES-CBC-SHA ciphers, the server offers none"
+
+// Renegotiation checks if the server accepts renegotiation initiated by
+// the client, which allows a DoS, and if it supports secure renegotiation
+// (RFC 5746) to protect against CVE-2009-3555
+type Renegotiation struct {
+	// Insecure is yes when secure renegotiation is not supported
+	Insecure string `json:"insecure"`
+	// ClientInitiated is yes when the server renegotiates on request of
+	// the client and n/a when no CBC cipher is available to test it
+	// (AEAD only servers), which Detail states
+	ClientInitiated     string `json:"clientInitiated"`
+	Detail              string `json:"detail,omitempty"`
+	SecureRenegotiation bool   `json:"secureRenegotiation"`
+	// Protocol is the protocol of the ServerHello
+	Protocol string `json:"protocol,omitempty"`
+}
+
+// Check tests secure renegotiation (see CheckSecure) and then
+// client-initiated renegotiation (see clientRenegotiation)
+func (v *Renegotiation) Check(host string, port string, opts Options) error {
+	if err := v.CheckSecure(host, port, opts); err != nil || v.Protocol == "" {
+		v.ClientInitiated = v.Insecure
+		return err
+	}
+
+	var err error
+	v.ClientInitiated, err = clientRenegotiation(host, port, opts)
+	if v.ClientInitiated == notApplicable {
+		v.Detail = noCBCDetail
+	}
+
+	logger.Debugf("event_id=renegotiation proto=%s client_initiated=%s secure=%v", v.Protocol, v.ClientInitiated, v.SecureRenegotiation)
+
+	return err
+}
+
+// CheckSecure reads the renegotiation_info extension from the ServerHello
+// of the highest protocol up to TLS 1.2, TLS 1.3 removed renegotiation
+func (v *Renegotiation) CheckSecure(host string, port string, opts Options) error {
+	var err error
+
+	for _, p := range []int{etls.VersionTLS12, etls.VersionTLS11, etls.VersionTLS10} {
+		hello := newClientHello(host, p, protocolCiphers(p))
+		hello.setGroups(kexGroups(p))
+		hello.setExtension(extRenegotiationInfo, []byte{0})
+
+		var flight *serverFlight
+		flight, err = rawHandshake(host, port, hello, opts)
+		if flight == nil || flight.hello == nil {
+			continue
+		}
+
+		v.Protocol = protocolVersionMap[int(flight.hello.version)]
+		_, v.SecureRenegotiation = flight.hello.extensions[extRenegotiationInfo]
+		break
+	}
+
+	if v.Protocol == "" {
+		if err == nil || isRefusal(err) {
+			v.Insecure = notApplicable
+			return nil
+		}
+		v.Insecure = testFailed
+		return err
+	}
+
+	v.Insecure = notVulnerable
+	if !v.SecureRenegotiation {
+		v.Insecure = vulnerable
+	}
+
+	return nil
+}
+
+// clientRenegotiation completes a handshake and sends a new ClientHello
+// over the encrypted connection. The records of the server's answer are
+// not decrypted, a handshake record means the server started
+// renegotiating. The handshake is done with a CBC cipher (see cbcSession),
+// so servers without one are not applicable
+func clientRenegotiation(host string, port string, opts Options) (string, error) {
+	s, err := cbcHandshake(host, port, sessionCipherList(), opts)
+	if err != nil {
+		if errors.Is(err, errUnsupportedSession) || isRefusal(err) {
+			return notApplicable, nil
+		}
+		return testFailed, err
+	}
+	defer s.Close()
+
+	hello := newClientHello(host, int(s.version), []uint16{s.cipher})
+	hello.setGroups(sessionCurves)
+	if _, secure := s.extensions[extRenegotiationInfo]; secure {
+		hello.setExtension(extRenegotiationInfo, append([]byte{byte(len(s.clientVerifyData))}, s.clientVerifyData...))
+	}
+
+	if err := s.writeRecord(recordTypeHandshake, hello.body(), false); err != nil {
+		return testFailed, err
+	}
+
+	s.conn.SetDeadline(time.Now().Add(5 * time.Second))
+	accepted, err := renegotiationAccepted(s.conn)
+	switch {
+	case err != nil:
+		return testFailed, err
+	case accepted:
+		return vulnerable, nil
+	}
+
+	return notVulnerable, nil
+}
+
+// renegotiationAccepted reads the server's records until it answers the
+// ClientHello with a handshake record, an alert or by closing the
+// connection. Servers that ignore the ClientHello refuse it as well
+func renegotiationAccepted(conn net.Conn) (bool, error) {
+	for {
+		typ, _, _, err := readRecord(conn)
+
+		var netErr net.Error
+		switch {
+		case errors.As(err, &netErr) && netErr.Timeout():
+			return false, nil
+		case isRefusal(err):
+			return false, nil
+		case err != nil:
+			return false, err
+		case typ == recordTypeHandshake:
+			return true, nil
+		case typ == recordTypeAlert:
+			// the alert is encrypted, most servers send no_renegotiation
+			return false, nil
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package ssl
+
+import (
+	"crypto/tls"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/jsandas/etls"
+)
+
+// startReadingServer runs a Go TLS server that reads from every connection
+// so post-handshake messages are processed
+func startReadingServer(t *testing.T, cfg *tls.Config) (string, string) {
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				io.Copy(io.Discard, conn)
+				conn.Close()
+			}()
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	return host, port
+}
+
+func TestRenegotiationGoServer(t *testing.T) {
+	host, port := startReadingServer(t, &tls.Config{
+		Certificates: []tls.Certificate{featureCertificate(t, false)},
+		CipherSuites: []uint16{etls.TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA},
+		MaxVersion:   uint16(etls.VersionTLS12),
+	})
+
+	var v Renegotiation
+	if err := v.Check(host, port, DefaultOptions()); err != nil {
+		t.Fatal(err)
+	}
+	if v.Insecure != notVulnerable || v.ClientInitiated != notVulnerable || !v.SecureRenegotiation || v.Protocol != "TLSv1.2" {
+		t.Errorf("wrong result, got: %+v", v)
+	}
+}
+
+func TestRenegotiationFakeServer(t *testing.T) {
+	tests := []struct {
+		renegotiate     bool
+		secure          bool
+		clientInitiated string
+		insecure        string
+	}{
+		{false, true, notVulnerable, notVulnerable},
+		{true, true, vulnerable, notVulnerable},
+		{false, false, notVulnerable, vulnerable},
+		{true, false, vulnerable, vulnerable},
+	}
+
+	for _, tt := range tests {
+		srv := newFakeSessionServer(t)
+		srv.renegotiate = tt.renegotiate
+		srv.secureRenegotiation = tt.secure
+		host, port := srv.start(t)
+
+		var v Renegotiation
+		if err := v.Check(host, port, DefaultOptions()); err != nil {
+			t.Fatal(err)
+		}
+		if v.Insecure != tt.insecure || v.ClientInitiated != tt.clientInitiated || v.SecureRenegotiation != tt.secure {
+			t.Errorf("wrong result for %+v, got: %+v", tt, v)
+		}
+	}
+}
+
+func TestRenegotiationNoCBC(t *testing.T) {
+	// secure renegotiation is read from the ServerHello of an AEAD cipher
+	host, port := startFeatureServer(t, &tls.Config{
+		Certificates: []tls.Certificate{featureCertificate(t, false)},
+		CipherSuites: []uint16{etls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256},
+		MaxVersion:   uint16(etls.VersionTLS12),
+	})
+
+	var v Renegotiation
+	if err := v.Check(host, port, DefaultOptions()); err != nil {
+		t.Fatal(err)
+	}
+	if v.Insecure != notVulnerable || v.ClientInitiated != notApplicable || v.Detail != noCBCDetail || !v.SecureRenegotiation || v.Protocol != "TLSv1.2" {
+		t.Errorf("wrong result, got: %+v", v)
+	}
+}
+
+func TestRenegotiationTLS13(t *testing.T) {
+	host, port := startFeatureServer(t, &tls.Config{
+		Certificates: []tls.Certificate{featureCertificate(t, false)},
+		MinVersion:   uint16(etls.VersionTLS13),
+	})
+
+	var v Renegotiation
+	v.Check(host, port, DefaultOptions())
+	if v.Insecure != notApplicable || v.ClientInitiated != notApplicable {
+		t.Errorf("wrong result, got: %+v", v)
+	}
+}
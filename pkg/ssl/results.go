@@ -0,0 +1,91 @@
+package ssl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CipherResult describes a cipher supported by the server
+type CipherResult struct {
+	// CodePoint is the IANA code point, e.g. 0xC02F, or the three byte
+	// cipher kind for SSLv2
+	CodePoint string `json:"codePoint"`
+	// Name is the IANA name of the cipher
+	Name string `json:"name"`
+	// OpenSSLName is the name used by OpenSSL
+	OpenSSLName    string `json:"opensslName,omitempty"`
+	KeyExchange    string `json:"keyExchange,omitempty"`
+	Authentication string `json:"authentication,omitempty"`
+	Encryption     string `json:"encryption,omitempty"`
+	MAC            string `json:"mac,omitempty"`
+	// Bits is the effective strength of the bulk cipher
+	Bits           int  `json:"bits"`
+	ForwardSecrecy bool `json:"forwardSecrecy"`
+	AEAD           bool `json:"aead"`
+	// KeyExchangeParams are the ephemeral key exchange parameters the
+	// server used with the cipher
+	KeyExchangeParams *KeyExchange `json:"keyExchangeParams,omitempty"`
+}
+
+// newCipherResult fills in the metadata known about the named cipher
+func newCipherResult(name string) CipherResult {
+	var r = CipherResult{Name: name}
+
+	id, ok := cipherID(name)
+	if !ok {
+		for kind, n := range sslv2Ciphers {
+			if n == name {
+				r.CodePoint = "0x" + strings.ToUpper(kind)
+			}
+		}
+		return r
+	}
+
+	c := cipherSuites[id]
+	r.CodePoint = fmt.Sprintf("0x%04X", id)
+	r.OpenSSLName = c.opensslName
+	r.KeyExchange = c.keyExhange
+	r.Authentication = c.authentication
+	r.Encryption = c.encryption
+	r.MAC = c.messageAuthCode
+	r.Bits = cipherBits(c.encryption)
+	r.ForwardSecrecy = isEphemeral(c, c.MinProtoVersion)
+	r.AEAD = c.messageAuthCode == "AEAD"
+
+	return r
+}
+
+// cipherBits returns the strength of an encryption such as AESGCM(256).
+// 3DES is reported at its effective strength of 112 bits
+func cipherBits(encryption string) int {
+	if strings.HasPrefix(encryption, "3DES") {
+		return 112
+	}
+
+	start := strings.Index(encryption, "(")
+	end := strings.Index(encryption, ")")
+	if start < 0 || end < start {
+		return 0
+	}
+
+	bits, err := strconv.Atoi(encryption[start+1 : end])
+	if err != nil {
+		return 0
+	}
+
+	return bits
+}
+
+// CipherNames returns only the cipher names of results, which is the
+// format of the original supportedConfig output
+func CipherNames(results map[string][]CipherResult) map[string][]string {
+	names := make(map[string][]string)
+	for p, ciphers := range results {
+		names[p] = []string{}
+		for _, c := range ciphers {
+			names[p] = append(names[p], c.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,94 @@
+package ssl
+
+import (
+	"testing"
+)
+
+func TestNewCipherResult(t *testing.T) {
+	var tests = []struct {
+		name string
+		want CipherResult
+	}{
+		{
+			name: "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+			want: CipherResult{
+				CodePoint:      "0xC02F",
+				Name:           "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+				OpenSSLName:    "ECDHE-RSA-AES128-GCM-SHA256",
+				KeyExchange:    "ECDHE",
+				Authentication: "RSA",
+				Encryption:     "AESGCM(128)",
+				MAC:            "AEAD",
+				Bits:           128,
+				ForwardSecrecy: true,
+				AEAD:           true,
+			},
+		},
+		{
+			name: "TLS_RSA_WITH_3DES_EDE_CBC_SHA",
+			want: CipherResult{
+				CodePoint:      "0x000A",
+				Name:           "TLS_RSA_WITH_3DES_EDE_CBC_SHA",
+				OpenSSLName:    "DES-CBC3-SHA",
+				KeyExchange:    "RSA",
+				Authentication: "RSA",
+				Encryption:     "3DES(168)",
+				MAC:            "SHA1",
+				Bits:           112,
+			},
+		},
+		{
+			name: "TLS_AES_256_GCM_SHA384",
+			want: CipherResult{
+				CodePoint:      "0x1302",
+				Name:           "TLS_AES_256_GCM_SHA384",
+				OpenSSLName:    "TLS_AES_256_GCM_SHA384",
+				KeyExchange:    "any",
+				Authentication: "any",
+				Encryption:     "AESGCM(256)",
+				MAC:            "AEAD",
+				Bits:           256,
+				ForwardSecrecy: true,
+				AEAD:           true,
+			},
+		},
+		{
+			name: "SSL2_RC4_128_WITH_MD5",
+			want: CipherResult{CodePoint: "0x010080", Name: "SSL2_RC4_128_WITH_MD5"},
+		},
+	}
+
+	for _, tc := range tests {
+		got := newCipherResult(tc.name)
+		if got != tc.want {
+			t.Errorf("wrong result for %s, got: %+v, want: %+v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestCipherBits(t *testing.T) {
+	var tests = map[string]int{
+		"AESGCM(256)":   256,
+		"ChaCha20(256)": 256,
+		"RC4(40)":       40,
+		"3DES(168)":     112,
+		"None":          0,
+	}
+
+	for enc, want := range tests {
+		if got := cipherBits(enc); got != want {
+			t.Errorf("wrong bits for %s, got: %d, want: %d", enc, got, want)
+		}
+	}
+}
+
+func TestCipherNames(t *testing.T) {
+	results := map[string][]CipherResult{
+		"TLSv1.2": {newCipherResult("TLS_RSA_WITH_AES_128_CBC_SHA"), newCipherResult("TLS_RSA_WITH_AES_256_CBC_SHA")},
+	}
+
+	names := CipherNames(results)
+	if len(names["TLSv1.2"]) != 2 || names["TLSv1.2"][1] != "TLS_RSA_WITH_AES_256_CBC_SHA" {
+		t.Errorf("wrong cipher names, got: %v", names)
+	}
+}
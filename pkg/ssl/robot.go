@@ -0,0 +1,361 @@
+package ssl
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"slices"
+	"time"
+
+	"github.com/jsandas/etls"
+	logger "github.com/jsandas/gologger"
+)
+
+// ROBOT oracle types
+const (
+	oracleStrong = "strong"
+	oracleWeak   = "weak"
+)
+
+// robotRounds is the number of times each variant is sent
+const robotRounds = 3
+
+// robotTimingGap is the minimum difference between the response times of
+// two variants that is reported as a timing oracle
+const robotTimingGap = 50 * time.Millisecond
+
+// robotTimeout is how long to wait for the server to react. Servers often
+// wait for ChangeCipherSpec when it is not sent, so a timeout is a response
+var robotTimeout = 3 * time.Second
+
+// errRobotInconsistent is returned when a variant gets different responses
+// across rounds
+var errRobotInconsistent = errors.New("robot_inconsistent_responses")
+
+// robotVariant builds a PKCS#1 v1.5 block of size k from k-51 bytes of
+// padding, the premaster secret version offered in the ClientHello and a
+// random 46 byte premaster secret body
+type robotVariant struct {
+	name  string
+	block func(k int, version []byte, pad []byte, pms []byte) []byte
+}
+
+// robotVariants are the malformed ClientKeyExchange messages of the ROBOT
+// paper. The first is well formed and the second has invalid first bytes
+var robotVariants = []robotVariant{
+	{
+		name: "valid",
+		block: func(k int, version []byte, pad []byte, pms []byte) []byte {
+			return robotBlock(k, []byte{0x00, 0x02}, pad, []byte{0x00}, version, pms)
+		},
+	},
+	{
+		name: "wrongFirstBytes",
+		block: func(k int, version []byte, pad []byte, pms []byte) []byte {
+			return robotBlock(k, []byte{0x41, 0x17}, pad, []byte{0x00}, version, pms)
+		},
+	},
+	{
+		// also triggers an older JSSE bug
+		name: "wrongSeparatorPosition",
+		block: func(k int, version []byte, pad []byte, pms []byte) []byte {
+			return robotBlock(k, []byte{0x00, 0x02}, pad, []byte{0x11}, pms, []byte{0x00, 0x11})
+		},
+	},
+	{
+		name: "noSeparator",
+		block: func(k int, version []byte, pad []byte, pms []byte) []byte {
+			return robotBlock(k, []byte{0x00, 0x02}, pad, []byte{0x11, 0x11, 0x11}, pms)
+		},
+	},
+	{
+		name: "wrongVersion",
+		block: func(k int, version []byte, pad []byte, pms []byte) []byte {
+			return robotBlock(k, []byte{0x00, 0x02}, pad, []byte{0x00, 0x02, 0x02}, pms)
+		},
+	},
+}
+
+// ROBOT checks if the RSA key exchange is a Bleichenbacher padding oracle
+// (Return Of Bleichenbacher's Oracle Threat)
+type ROBOT struct {
+	Vulnerable string `json:"vulnerable"`
+	// Oracle is strong or weak for vulnerable servers
+	Oracle string `json:"oracle,omitempty"`
+	// Protocol is the protocol the ClientKeyExchange messages were sent with
+	Protocol  string          `json:"protocol,omitempty"`
+	Responses []ROBOTResponse `json:"responses,omitempty"`
+}
+
+// ROBOTResponse is the server's reaction to a ClientKeyExchange variant
+type ROBOTResponse struct {
+	Variant  string `json:"variant"`
+	Response string `json:"response"`
+	// Time is the median response time in milliseconds
+	Time int64 `json:"time"`
+}
+
+// robotResult is the response to one ClientKeyExchange
+type robotResult struct {
+	response string
+	elapsed  time.Duration
+}
+
+// Check sends the ROBOT variants with and, if the server does not
+// distinguish them, without ChangeCipherSpec and Finished
+func (v *ROBOT) Check(host string, port string, opts Options) error {
+	p, key, err := robotTarget(host, port, opts)
+	if err != nil {
+		if errors.Is(err, errUnsupportedSession) || isRefusal(err) {
+			v.Vulnerable = notApplicable
+			return nil
+		}
+		v.Vulnerable = testFailed
+		return err
+	}
+	v.Protocol = protocolVersionMap[p]
+
+	var inconsistent error
+	for _, finished := range []bool{true, false} {
+		results, err := robotResults(host, port, p, key, finished, opts)
+		if err != nil {
+			v.Vulnerable = testFailed
+			return err
+		}
+
+		v.Responses = robotResponses(results)
+		v.Oracle, err = robotOracle(results)
+		if err != nil {
+			inconsistent = err
+		}
+		if v.Oracle != "" {
+			break
+		}
+	}
+
+	logger.Debugf("event_id=robot proto=%s oracle=%s responses=%v", v.Protocol, v.Oracle, v.Responses)
+
+	switch {
+	case v.Oracle != "":
+		v.Vulnerable = vulnerable
+	case inconsistent != nil:
+		// an oracle may be hidden by the noise
+		v.Vulnerable = testFailed
+		return inconsistent
+	default:
+		v.Vulnerable = notVulnerable
+	}
+
+	return nil
+}
+
+// robotTarget returns the protocol negotiated with an RSA key exchange
+// and the server's RSA key
+func robotTarget(host string, port string, opts Options) (int, *rsa.PublicKey, error) {
+	var err error = errUnsupportedSession
+
+	for _, p := range []int{etls.VersionTLS12, etls.VersionTLS11, etls.VersionTLS10} {
+		var s *cbcSession
+		s, err = startSession(host, port, p, rsaCiphers(p), opts)
+		if err != nil {
+			continue
+		}
+		s.Close()
+
+		key, err := s.serverRSAKey()
+		return int(s.version), key, err
+	}
+
+	return 0, nil, err
+}
+
+// rsaCiphers returns the ciphers using RSA key transport for protocol p
+func rsaCiphers(p int) []uint16 {
+	var ciphers []uint16
+	for _, id := range protocolCiphers(p) {
+		if cipherSuites[id].keyExhange == "RSA" {
+			ciphers = append(ciphers, id)
+		}
+	}
+	return ciphers
+}
+
+// robotResults sends every variant robotRounds times, one at a time as
+// concurrent connections skew the response times. Servers answering every
+// variant of the first round alike and in similar time are not sent further
+// rounds
+func robotResults(host string, port string, p int, key *rsa.PublicKey, finished bool, opts Options) ([][]robotResult, error) {
+	k := key.Size()
+	version := uint16Bytes(uint16(p))
+	pad := make([]byte, k-3-48)
+	pms := make([]byte, 46)
+	rand.Read(pms)
+	for i := range pad {
+		// padding bytes must not be zero
+		pad[i] = byte(1 + i%255)
+	}
+
+	results := make([][]robotResult, len(robotVariants))
+	for round := 0; round < robotRounds; round++ {
+		for i, variant := range robotVariants {
+			cke := rsaRaw(key, variant.block(k, version, pad, pms))
+			res, err := robotSend(host, port, p, cke, finished, opts)
+			if err != nil {
+				return nil, err
+			}
+			results[i] = append(results[i], res)
+		}
+
+		// further rounds can only make the responses inconsistent
+		if oracle, _ := robotOracle(results); oracle == "" {
+			break
+		}
+	}
+
+	return results, nil
+}
+
+// robotSend completes a handshake up to the ClientKeyExchange and returns
+// the server's response to it
+func robotSend(host string, port string, p int, encrypted []byte, finished bool, opts Options) (robotResult, error) {
+	var res robotResult
+
+	s, err := startSession(host, port, p, rsaCiphers(p), opts)
+	if err != nil {
+		return res, err
+	}
+	defer s.Close()
+
+	out := record(recordTypeHandshake, s.version, handshakeMessage(typeClientKeyExchange, append(uint16Bytes(uint16(len(encrypted))), encrypted...)))
+	if finished {
+		// the keys are unknown, so Finished is random bytes
+		garbage := make([]byte, 64)
+		rand.Read(garbage)
+		out = append(out, record(recordTypeChangeCipherSpec, s.version, []byte{1})...)
+		out = append(out, record(recordTypeHandshake, s.version, garbage)...)
+	}
+
+	s.conn.SetDeadline(time.Now().Add(robotTimeout))
+	start := time.Now()
+	if _, err := s.conn.Write(out); err != nil {
+		return res, err
+	}
+
+	res.response = robotResponse(s.conn)
+	res.elapsed = time.Since(start)
+
+	return res, nil
+}
+
+// robotResponse describes how the server reacted: an alert, a closed or
+// reset connection, a timeout or another record
+func robotResponse(conn net.Conn) string {
+	typ, _, fragment, err := readRecord(conn)
+
+	var netErr net.Error
+	switch {
+	case err == nil && typ == recordTypeAlert && len(fragment) == 2:
+		return fmt.Sprintf("alert %d", fragment[1])
+	case err == nil:
+		return fmt.Sprintf("record %d", typ)
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return "timeout"
+	case isRefusal(err):
+		// the connection was closed or reset after the alert
+		if errors.As(err, &netErr) {
+			return "reset"
+		}
+		return "closed"
+	}
+
+	return err.Error()
+}
+
+// robotOracle classifies the results. Servers with a strong oracle tell a
+// message with invalid first bytes apart from the other malformed
+// messages, servers with a weak oracle only recognise a fully valid block.
+// Responses differing across rounds return errRobotInconsistent
+func robotOracle(results [][]robotResult) (string, error) {
+	responses := make([]string, len(results))
+	for i, r := range results {
+		for _, res := range r {
+			if res.response != r[0].response {
+				logger.Debugf("event_id=robot_inconsistent variant=%s", robotVariants[i].name)
+				return "", errRobotInconsistent
+			}
+		}
+		responses[i] = r[0].response
+	}
+
+	if slices.IndexFunc(responses, func(r string) bool { return r != responses[0] }) != -1 {
+		if responses[1] == responses[2] && responses[1] == responses[3] {
+			return oracleWeak, nil
+		}
+		return oracleStrong, nil
+	}
+
+	// identical alerts, compare each variant's timing with the variant that
+	// fails on the first bytes
+	for i := range results {
+		if i != 1 && timingDiffers(results[i], results[1]) {
+			logger.Debugf("event_id=robot_timing_oracle variant=%s", robotVariants[i].name)
+			return oracleWeak, nil
+		}
+	}
+
+	return "", nil
+}
+
+// timingDiffers reports if every response of a is faster or slower than
+// every response of b by at least robotTimingGap
+func timingDiffers(a []robotResult, b []robotResult) bool {
+	minA, maxA := timingRange(a)
+	minB, maxB := timingRange(b)
+	return minA-maxB >= robotTimingGap || minB-maxA >= robotTimingGap
+}
+
+func timingRange(results []robotResult) (time.Duration, time.Duration) {
+	lo, hi := results[0].elapsed, results[0].elapsed
+	for _, r := range results {
+		lo = min(lo, r.elapsed)
+		hi = max(hi, r.elapsed)
+	}
+	return lo, hi
+}
+
+// robotResponses summarises the results as evidence
+func robotResponses(results [][]robotResult) []ROBOTResponse {
+	var responses []ROBOTResponse
+	for i, r := range results {
+		times := make([]time.Duration, len(r))
+		for j, res := range r {
+			times[j] = res.elapsed
+		}
+		slices.Sort(times)
+
+		responses = append(responses, ROBOTResponse{
+			Variant:  robotVariants[i].name,
+			Response: r[0].response,
+			Time:     times[len(times)/2].Milliseconds(),
+		})
+	}
+	return responses
+}
+
+// robotBlock concatenates the parts of a k byte block
+func robotBlock(k int, parts ...[]byte) []byte {
+	b := make([]byte, 0, k)
+	for _, part := range parts {
+		b = append(b, part...)
+	}
+	return b
+}
+
+// rsaRaw encrypts m with the public key without padding
+func rsaRaw(key *rsa.PublicKey, m []byte) []byte {
+	c := new(big.Int).Exp(new(big.Int).SetBytes(m), big.NewInt(int64(key.E)), key.N)
+	return c.FillBytes(make([]byte, key.Size()))
+}
@@ -0,0 +1,172 @@
+package ssl
+
+import (
+	"bytes"
+	"crypto/tls"
+	"testing"
+	"time"
+
+	"github.com/jsandas/etls"
+)
+
+// robotSeparator returns the position of the zero byte ending the padding
+func robotSeparator(block []byte) int {
+	if i := bytes.IndexByte(block[2:], 0); i >= 0 {
+		return 2 + i
+	}
+	return -1
+}
+
+// shortRobotTimeout lowers the response timeout for the test
+func shortRobotTimeout(t *testing.T) {
+	timeout := robotTimeout
+	robotTimeout = time.Second
+	t.Cleanup(func() { robotTimeout = timeout })
+}
+
+func TestROBOTFakeServer(t *testing.T) {
+	shortRobotTimeout(t)
+
+	tests := []struct {
+		name   string
+		robot  func(block []byte) (byte, time.Duration)
+		oracle string
+	}{
+		{
+			name:   "constant",
+			robot:  func(block []byte) (byte, time.Duration) { return 0, 0 },
+			oracle: "",
+		},
+		{
+			// the first bytes are checked before the rest of the block
+			name: "strong",
+			robot: func(block []byte) (byte, time.Duration) {
+				if block[0] != 0 || block[1] != 2 {
+					return 51, 0
+				}
+				return 0, 0
+			},
+			oracle: oracleStrong,
+		},
+		{
+			// only blocks with a 48 byte premaster secret are told apart
+			name: "weak",
+			robot: func(block []byte) (byte, time.Duration) {
+				if block[0] != 0 || block[1] != 2 || robotSeparator(block) != len(block)-49 {
+					return 51, 0
+				}
+				return 0, 0
+			},
+			oracle: oracleWeak,
+		},
+		{
+			name: "timing",
+			robot: func(block []byte) (byte, time.Duration) {
+				if block[0] == 0 && block[1] == 2 {
+					return 0, 300 * time.Millisecond
+				}
+				return 0, 0
+			},
+			oracle: oracleWeak,
+		},
+	}
+
+	for _, tt := range tests {
+		srv := newFakeSessionServer(t)
+		srv.robot = tt.robot
+		host, port := srv.start(t)
+
+		var v ROBOT
+		if err := v.Check(host, port, DefaultOptions()); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+
+		want := notVulnerable
+		if tt.oracle != "" {
+			want = vulnerable
+		}
+		if v.Vulnerable != want || v.Oracle != tt.oracle {
+			t.Errorf("%s: wrong result, got: %s/%s, want: %s/%s, responses: %+v", tt.name, v.Vulnerable, v.Oracle, want, tt.oracle, v.Responses)
+		}
+		if len(v.Responses) != len(robotVariants) {
+			t.Errorf("%s: wrong response count, got: %d", tt.name, len(v.Responses))
+		}
+	}
+}
+
+func TestROBOTTLS10(t *testing.T) {
+	shortRobotTimeout(t)
+
+	// the premaster secret version is checked against the ClientHello
+	srv := newFakeSessionServer(t)
+	srv.maxVersion = etls.VersionTLS10
+	srv.robot = func(block []byte) (byte, time.Duration) {
+		sep := robotSeparator(block)
+		if block[0] != 0 || block[1] != 2 || sep != len(block)-49 || !bytes.Equal(block[sep+1:sep+3], []byte{0x03, 0x01}) {
+			return 51, 0
+		}
+		return 0, 0
+	}
+	host, port := srv.start(t)
+
+	var v ROBOT
+	if err := v.Check(host, port, DefaultOptions()); err != nil {
+		t.Fatal(err)
+	}
+	if v.Vulnerable != vulnerable || v.Oracle != oracleWeak || v.Protocol != "TLSv1.0" {
+		t.Errorf("wrong result, got: %+v", v)
+	}
+}
+
+func TestROBOTInconsistent(t *testing.T) {
+	results := [][]robotResult{
+		{{response: "alert 51"}, {response: "alert 51"}},
+		{{response: "alert 51"}, {response: "timeout"}},
+	}
+	if oracle, err := robotOracle(results); oracle != "" || err != errRobotInconsistent {
+		t.Errorf("inconsistent responses should fail, got: %s %v", oracle, err)
+	}
+}
+
+func TestROBOTGoServer(t *testing.T) {
+	shortRobotTimeout(t)
+
+	host, port := startFeatureServer(t, &tls.Config{
+		Certificates: []tls.Certificate{rsaCertificate(t, testRSAKey(t))},
+		CipherSuites: []uint16{etls.TLS_RSA_WITH_AES_128_CBC_SHA},
+		MaxVersion:   uint16(etls.VersionTLS12),
+	})
+
+	var v ROBOT
+	if err := v.Check(host, port, DefaultOptions()); err != nil {
+		t.Fatal(err)
+	}
+	if v.Vulnerable != notVulnerable || v.Protocol != "TLSv1.2" {
+		t.Errorf("wrong result, got: %+v", v)
+	}
+}
+
+func TestROBOTNoRSA(t *testing.T) {
+	host, port := startFeatureServer(t, &tls.Config{
+		Certificates: []tls.Certificate{featureCertificate(t, false)},
+		MaxVersion:   uint16(etls.VersionTLS12),
+	})
+
+	var v ROBOT
+	v.Check(host, port, DefaultOptions())
+	if v.Vulnerable != notApplicable {
+		t.Errorf("wrong result, got: %s, want: %s", v.Vulnerable, notApplicable)
+	}
+}
+
+func TestROBOTVariants(t *testing.T) {
+	const k = 256
+	pad := bytes.Repeat([]byte{0xff}, k-51)
+	pms := make([]byte, 46)
+
+	for _, variant := range robotVariants {
+		if got := len(variant.block(k, []byte{0x03, 0x03}, pad, pms)); got != k {
+			t.Errorf("wrong block size for %s, got: %d, want: %d", variant.name, got, k)
+		}
+	}
+}
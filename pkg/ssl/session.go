@@ -0,0 +1,460 @@
+package ssl
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"net"
+	"slices"
+	"time"
+
+	"github.com/jsandas/etls"
+)
+
+/*
+	A minimal TLS 1.0 - 1.2 client for the checks that have to complete
+	a handshake and then send records the etls client will not produce
+	(bad CBC padding, malformed ClientKeyExchange, renegotiation).
+	Only AES-CBC-SHA suites with RSA or ECDHE key exchange are implemented
+	and the server's signatures and Finished message are not verified.
+*/
+
+// additional handshake message types used by the session
+const (
+	typeClientKeyExchange = 16
+	typeFinished          = 20
+)
+
+// TLS record type for application data
+const recordTypeApplicationData = 23
+
+// sessionCiphers are the suites supported by cbcSession and their key size
+var sessionCiphers = map[uint16]int{
+	etls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA:   16,
+	etls.TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA:   32,
+	etls.TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA: 16,
+	etls.TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA: 32,
+	etls.TLS_RSA_WITH_AES_128_CBC_SHA:         16,
+	etls.TLS_RSA_WITH_AES_256_CBC_SHA:         32,
+}
+
+// sessionCurves are the curves offered by cbcSession
+var sessionCurves = []uint16{29, 23, 24}
+
+var errUnsupportedSession = errors.New("unsupported_session_parameters")
+
+// cbcSession is a TLS connection with a CBC cipher suite
+type cbcSession struct {
+	conn    net.Conn
+	hr      *handshakeReader
+	version uint16
+	cipher  uint16
+	// helloVersion is the version of the ClientHello, which the RSA
+	// premaster secret carries to detect version rollback
+	helloVersion uint16
+
+	clientRandom []byte
+	serverRandom []byte
+	extensions   map[uint16][]byte
+	certificates [][]byte
+	keyExchange  []byte
+	transcript   []byte
+	masterSecret []byte
+	// clientVerifyData is the verify_data of the client's Finished
+	clientVerifyData []byte
+
+	write *recordCipher
+}
+
+// startSession connects and reads the server's flight up to
+// ServerHelloDone for protocol p offering ciphers. Any of the offered
+// ciphers is accepted but only sessionCiphers can finish the handshake
+func startSession(host string, port string, p int, ciphers []uint16, opts Options) (*cbcSession, error) {
+	conn, err := rawDial(host, port, opts)
+	if err != nil {
+		return nil, err
+	}
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	hello := newClientHello(host, p, ciphers)
+	hello.setGroups(sessionCurves)
+	hello.setExtension(extRenegotiationInfo, []byte{0})
+	hello.setStartTLS(opts.StartTLS)
+
+	s := &cbcSession{
+		conn:         conn,
+		hr:           &handshakeReader{r: conn},
+		helloVersion: hello.version,
+		clientRandom: hello.random,
+	}
+
+	s.transcript = hello.body()
+	if _, err := conn.Write(hello.marshal()); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if err := s.readServerFlight(ciphers); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *cbcSession) readServerFlight(offered []uint16) error {
+	for {
+		typ, body, err := s.hr.next()
+		if err != nil {
+			return err
+		}
+		s.transcript = append(s.transcript, handshakeMessage(typ, body)...)
+
+		switch typ {
+		case typeServerHello:
+			hello, err := parseServerHello(body)
+			if err != nil {
+				return err
+			}
+			if !slices.Contains(offered, hello.cipher) || hello.version < etls.VersionTLS10 || hello.version > etls.VersionTLS12 {
+				return errUnsupportedSession
+			}
+			s.version = hello.version
+			s.cipher = hello.cipher
+			s.serverRandom = hello.random
+			s.extensions = hello.extensions
+		case typeCertificate:
+			s.certificates = parseCertificates(body)
+		case typeServerKeyExchange:
+			s.keyExchange = body
+		case typeServerHelloDone:
+			return nil
+		}
+	}
+}
+
+// isRSAKeyExchange reports if the negotiated suite uses RSA key transport
+func (s *cbcSession) isRSAKeyExchange() bool {
+	return cipherSuites[s.cipher].keyExhange == "RSA"
+}
+
+// serverRSAKey returns the RSA public key of the server certificate
+func (s *cbcSession) serverRSAKey() (*rsa.PublicKey, error) {
+	if len(s.certificates) == 0 {
+		return nil, errUnsupportedSession
+	}
+	cert, err := x509.ParseCertificate(s.certificates[0])
+	if err != nil {
+		return nil, err
+	}
+	key, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, errUnsupportedSession
+	}
+	return key, nil
+}
+
+// clientKeyExchange returns the premaster secret and the ClientKeyExchange body
+func (s *cbcSession) clientKeyExchange() (premaster []byte, cke []byte, err error) {
+	if s.isRSAKeyExchange() {
+		key, err := s.serverRSAKey()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		premaster = make([]byte, 48)
+		rand.Read(premaster)
+		binary.BigEndian.PutUint16(premaster, s.helloVersion)
+
+		encrypted, err := rsa.EncryptPKCS1v15(rand.Reader, key, premaster)
+		if err != nil {
+			return nil, nil, err
+		}
+		return premaster, append(uint16Bytes(uint16(len(encrypted))), encrypted...), nil
+	}
+
+	curveID, ok := parseECParams(s.keyExchange)
+	curve, supported := keyShareCurves[curveID]
+	if !ok || !supported || len(s.keyExchange) < 4 || len(s.keyExchange) < 4+int(s.keyExchange[3]) {
+		return nil, nil, errUnsupportedSession
+	}
+
+	serverKey, err := curve.NewPublicKey(s.keyExchange[4 : 4+int(s.keyExchange[3])])
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := curve.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	premaster, err = key.ECDH(serverKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pub := key.PublicKey().Bytes()
+	return premaster, append([]byte{byte(len(pub))}, pub...), nil
+}
+
+// finishHandshake sends ClientKeyExchange, ChangeCipherSpec and Finished.
+// The keys are derived from premaster, which does not have to match cke
+func (s *cbcSession) finishHandshake(premaster []byte, cke []byte) error {
+	keyLen, ok := sessionCiphers[s.cipher]
+	if !ok {
+		return errUnsupportedSession
+	}
+
+	msg := handshakeMessage(typeClientKeyExchange, cke)
+	s.transcript = append(s.transcript, msg...)
+
+	s.masterSecret = prf(s.version, premaster, "master secret", concat(s.clientRandom, s.serverRandom), 48)
+	s.write, _ = newRecordCiphers(s.version, s.masterSecret, s.clientRandom, s.serverRandom, keyLen)
+
+	s.clientVerifyData = finishedData(s.version, s.masterSecret, "client finished", s.transcript)
+	finished := handshakeMessage(typeFinished, s.clientVerifyData)
+	s.transcript = append(s.transcript, finished...)
+
+	out := record(recordTypeHandshake, s.version, msg)
+	out = append(out, record(recordTypeChangeCipherSpec, s.version, []byte{1})...)
+	out = append(out, s.write.seal(recordTypeHandshake, finished, false)...)
+
+	_, err := s.conn.Write(out)
+	return err
+}
+
+// readServerFinished waits for the server's ChangeCipherSpec and Finished
+func (s *cbcSession) readServerFinished() error {
+	ccs := false
+	for {
+		typ, _, fragment, err := readRecord(s.conn)
+		if err != nil {
+			return err
+		}
+
+		switch typ {
+		case recordTypeChangeCipherSpec:
+			ccs = true
+		case recordTypeAlert:
+			if len(fragment) == 2 && !ccs {
+				return alertError{level: fragment[0], description: fragment[1]}
+			}
+			return errors.New("encrypted_alert")
+		case recordTypeHandshake:
+			if ccs {
+				return nil
+			}
+			// NewSessionTicket is sent before ChangeCipherSpec
+		}
+	}
+}
+
+// handshake completes a full handshake
+func (s *cbcSession) handshake() error {
+	premaster, cke, err := s.clientKeyExchange()
+	if err != nil {
+		return err
+	}
+	if err := s.finishHandshake(premaster, cke); err != nil {
+		return err
+	}
+	return s.readServerFinished()
+}
+
+// writeRecord sends an encrypted record
+func (s *cbcSession) writeRecord(typ uint8, data []byte, badPadding bool) error {
+	_, err := s.conn.Write(s.write.seal(typ, data, badPadding))
+	return err
+}
+
+func (s *cbcSession) Close() error {
+	return s.conn.Close()
+}
+
+// recordCipher protects records in one direction with AES-CBC and HMAC-SHA1
+type recordCipher struct {
+	version uint16
+	block   cipher.Block
+	macKey  []byte
+	// iv is the chained IV used by TLS 1.0
+	iv  []byte
+	seq uint64
+}
+
+// newRecordCiphers derives the client and server write ciphers
+func newRecordCiphers(version uint16, master []byte, clientRandom []byte, serverRandom []byte, keyLen int) (client *recordCipher, server *recordCipher) {
+	const macLen, ivLen = sha1.Size, aes.BlockSize
+
+	kb := prf(version, master, "key expansion", concat(serverRandom, clientRandom), 2*(macLen+keyLen+ivLen))
+
+	clientMAC, kb := kb[:macLen], kb[macLen:]
+	serverMAC, kb := kb[:macLen], kb[macLen:]
+	clientKey, kb := kb[:keyLen], kb[keyLen:]
+	serverKey, kb := kb[:keyLen], kb[keyLen:]
+	clientIV, serverIV := kb[:ivLen], kb[ivLen:]
+
+	cb, _ := aes.NewCipher(clientKey)
+	sb, _ := aes.NewCipher(serverKey)
+
+	client = &recordCipher{version: version, block: cb, macKey: clientMAC, iv: clientIV}
+	server = &recordCipher{version: version, block: sb, macKey: serverMAC, iv: serverIV}
+
+	return client, server
+}
+
+func (c *recordCipher) mac(typ uint8, data []byte) []byte {
+	h := hmac.New(sha1.New, c.macKey)
+	seq := make([]byte, 8)
+	binary.BigEndian.PutUint64(seq, c.seq)
+	h.Write(seq)
+	h.Write([]byte{typ})
+	h.Write(uint16Bytes(c.version))
+	h.Write(uint16Bytes(uint16(len(data))))
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// seal returns data as an encrypted record. With badPadding the padding
+// bytes other than the length byte are corrupted, which is what
+// TLS-POODLE checks for
+func (c *recordCipher) seal(typ uint8, data []byte, badPadding bool) []byte {
+	bs := c.block.BlockSize()
+
+	plain := concat(data, c.mac(typ, data))
+	c.seq++
+
+	padLen := (bs - (len(plain)+1)%bs) % bs
+	if badPadding && padLen == 0 {
+		padLen = bs
+	}
+	padding := bytes.Repeat([]byte{byte(padLen)}, padLen+1)
+	if badPadding {
+		padding[0] ^= 0xff
+	}
+	plain = append(plain, padding...)
+
+	iv := c.iv
+	var out []byte
+	if c.version >= etls.VersionTLS11 {
+		iv = make([]byte, bs)
+		rand.Read(iv)
+		out = append(out, iv...)
+	}
+
+	encrypted := make([]byte, len(plain))
+	cipher.NewCBCEncrypter(c.block, iv).CryptBlocks(encrypted, plain)
+	if c.version < etls.VersionTLS11 {
+		c.iv = encrypted[len(encrypted)-bs:]
+	}
+
+	return record(typ, c.version, append(out, encrypted...))
+}
+
+// open decrypts a record fragment. The padding contents are only
+// verified with checkPadding
+func (c *recordCipher) open(typ uint8, fragment []byte, checkPadding bool) ([]byte, error) {
+	bs := c.block.BlockSize()
+
+	iv := c.iv
+	if c.version >= etls.VersionTLS11 {
+		if len(fragment) < bs {
+			return nil, errMessageTooShort
+		}
+		iv, fragment = fragment[:bs], fragment[bs:]
+	}
+	if len(fragment) == 0 || len(fragment)%bs != 0 {
+		return nil, errMessageTooShort
+	}
+
+	plain := make([]byte, len(fragment))
+	cipher.NewCBCDecrypter(c.block, iv).CryptBlocks(plain, fragment)
+	if c.version < etls.VersionTLS11 {
+		c.iv = fragment[len(fragment)-bs:]
+	}
+
+	padLen := int(plain[len(plain)-1])
+	if padLen+1+sha1.Size > len(plain) {
+		return nil, errors.New("bad_record_mac")
+	}
+	if checkPadding {
+		for _, b := range plain[len(plain)-padLen-1:] {
+			if int(b) != padLen {
+				return nil, errors.New("bad_record_mac")
+			}
+		}
+	}
+	plain = plain[:len(plain)-padLen-1]
+
+	data, mac := plain[:len(plain)-sha1.Size], plain[len(plain)-sha1.Size:]
+	if !hmac.Equal(mac, c.mac(typ, data)) {
+		return nil, errors.New("bad_record_mac")
+	}
+	c.seq++
+
+	return data, nil
+}
+
+// prf is the TLS pseudorandom function for version
+func prf(version uint16, secret []byte, label string, seed []byte, n int) []byte {
+	seed = concat([]byte(label), seed)
+
+	if version >= etls.VersionTLS12 {
+		return pHash(sha256.New, secret, seed, n)
+	}
+
+	half := (len(secret) + 1) / 2
+	out := pHash(md5.New, secret[:half], seed, n)
+	for i, b := range pHash(sha1.New, secret[len(secret)-half:], seed, n) {
+		out[i] ^= b
+	}
+	return out
+}
+
+func pHash(h func() hash.Hash, secret []byte, seed []byte, n int) []byte {
+	var out []byte
+
+	mac := hmac.New(h, secret)
+	mac.Write(seed)
+	a := mac.Sum(nil)
+
+	for len(out) < n {
+		mac.Reset()
+		mac.Write(a)
+		mac.Write(seed)
+		out = append(out, mac.Sum(nil)...)
+
+		mac.Reset()
+		mac.Write(a)
+		a = mac.Sum(nil)
+	}
+
+	return out[:n]
+}
+
+// finishedData computes the verify_data of a Finished message
+func finishedData(version uint16, master []byte, label string, transcript []byte) []byte {
+	var digest []byte
+	if version >= etls.VersionTLS12 {
+		d := sha256.Sum256(transcript)
+		digest = d[:]
+	} else {
+		m := md5.Sum(transcript)
+		s := sha1.Sum(transcript)
+		digest = concat(m[:], s[:])
+	}
+	return prf(version, master, label, digest, 12)
+}
+
+func concat(a []byte, b []byte) []byte {
+	out := make([]byte, 0, len(a)+len(b))
+	return append(append(out, a...), b...)
+}
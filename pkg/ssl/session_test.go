@@ -0,0 +1,280 @@
+package ssl
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"math/big"
+	"net"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jsandas/etls"
+)
+
+var (
+	testRSAKeyOnce sync.Once
+	testRSAKeyPriv *rsa.PrivateKey
+)
+
+// testRSAKey returns an RSA key shared by the tests
+func testRSAKey(t *testing.T) *rsa.PrivateKey {
+	testRSAKeyOnce.Do(func() {
+		testRSAKeyPriv, _ = rsa.GenerateKey(rand.Reader, 2048)
+	})
+	if testRSAKeyPriv == nil {
+		t.Fatal("failed to generate rsa key")
+	}
+	return testRSAKeyPriv
+}
+
+// rsaCertificate returns a self-signed certificate for key
+func rsaCertificate(t *testing.T, key *rsa.PrivateKey) tls.Certificate {
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+// fakeSessionServer is a TLS 1.0 - 1.2 server for TLS_RSA_WITH_AES_128_CBC_SHA
+// built from the cbcSession primitives. Unlike a real server it can skip
+// the padding check of received records and react to the decrypted
+// PKCS#1 block of the ClientKeyExchange before the handshake continues:
+// robot returns an alert to send in clear (0 to continue) and a delay.
+// A ClientHello after the handshake is answered with a handshake record
+// when renegotiate is set and with no_renegotiation otherwise. maxVersion
+// caps the negotiated version when set. The premaster secret must carry the
+// ClientHello version
+type fakeSessionServer struct {
+	key                 *rsa.PrivateKey
+	maxVersion          uint16
+	cert                []byte
+	checkPadding        bool
+	robot               func(block []byte) (alert byte, delay time.Duration)
+	renegotiate         bool
+	secureRenegotiation bool
+}
+
+func newFakeSessionServer(t *testing.T) *fakeSessionServer {
+	key := testRSAKey(t)
+	return &fakeSessionServer{key: key, cert: rsaCertificate(t, key).Certificate[0], checkPadding: true}
+}
+
+func (s *fakeSessionServer) start(t *testing.T) (string, string) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.handle(conn)
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	return host, port
+}
+
+func (s *fakeSessionServer) handle(conn net.Conn) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	hr := &handshakeReader{r: conn}
+	typ, hello, err := hr.next()
+	if err != nil || typ != typeClientHello || len(hello) < 34 {
+		return
+	}
+
+	helloVersion := uint16(hello[0])<<8 | uint16(hello[1])
+	version := helloVersion
+	if version > etls.VersionTLS12 {
+		version = etls.VersionTLS12
+	}
+	if s.maxVersion != 0 && version > s.maxVersion {
+		version = s.maxVersion
+	}
+	clientRandom := hello[2:34]
+	serverRandom := make([]byte, 32)
+	rand.Read(serverRandom)
+
+	sh := uint16Bytes(version)
+	sh = append(sh, serverRandom...)
+	sh = append(sh, 0)
+	sh = append(sh, uint16Bytes(etls.TLS_RSA_WITH_AES_128_CBC_SHA)...)
+	sh = append(sh, 0)
+	if s.secureRenegotiation {
+		sh = append(sh, 0, 5)
+		sh = append(sh, uint16Bytes(extRenegotiationInfo)...)
+		sh = append(sh, 0, 1, 0)
+	}
+
+	certs := append([]byte{0, 0, 0}, byte(len(s.cert)>>16), byte(len(s.cert)>>8), byte(len(s.cert)))
+	certs = append(certs, s.cert...)
+	l := len(certs) - 3
+	certs[0], certs[1], certs[2] = byte(l>>16), byte(l>>8), byte(l)
+
+	flight := handshakeMessage(typeServerHello, sh)
+	flight = append(flight, handshakeMessage(typeCertificate, certs)...)
+	flight = append(flight, handshakeMessage(typeServerHelloDone, nil)...)
+	conn.Write(record(recordTypeHandshake, version, flight))
+
+	transcript := concat(handshakeMessage(typeClientHello, hello), flight)
+
+	typ, cke, err := hr.next()
+	if err != nil || typ != typeClientKeyExchange || len(cke) < 2 {
+		return
+	}
+	transcript = append(transcript, handshakeMessage(typ, cke)...)
+
+	if s.robot != nil {
+		c := new(big.Int).SetBytes(cke[2:])
+		block := new(big.Int).Exp(c, s.key.D, s.key.N).FillBytes(make([]byte, s.key.Size()))
+		alert, delay := s.robot(block)
+		time.Sleep(delay)
+		if alert != 0 {
+			conn.Write(record(recordTypeAlert, version, []byte{2, alert}))
+			return
+		}
+	}
+
+	premaster := make([]byte, 48)
+	rand.Read(premaster)
+	decrypted := slices.Clone(premaster)
+	// like real servers, a premaster not stamped with the ClientHello
+	// version is replaced by random bytes and fails the Finished check
+	if rsa.DecryptPKCS1v15SessionKey(nil, s.key, cke[2:], decrypted) == nil && binary.BigEndian.Uint16(decrypted) == helloVersion {
+		premaster = decrypted
+	}
+
+	master := prf(version, premaster, "master secret", concat(clientRandom, serverRandom), 48)
+	client, server := newRecordCiphers(version, master, clientRandom, serverRandom, 16)
+
+	// ChangeCipherSpec and the encrypted Finished
+	if typ, _, _, err := readRecord(conn); err != nil || typ != recordTypeChangeCipherSpec {
+		return
+	}
+	_, _, fragment, err := readRecord(conn)
+	if err != nil {
+		return
+	}
+	finished, err := client.open(recordTypeHandshake, fragment, true)
+	if err != nil {
+		return
+	}
+	transcript = append(transcript, finished...)
+
+	out := record(recordTypeChangeCipherSpec, version, []byte{1})
+	out = append(out, server.seal(recordTypeHandshake, handshakeMessage(typeFinished, finishedData(version, master, "server finished", transcript)), false)...)
+	conn.Write(out)
+
+	for {
+		typ, _, fragment, err := readRecord(conn)
+		if err != nil {
+			return
+		}
+		if _, err := client.open(typ, fragment, s.checkPadding); err != nil {
+			// bad_record_mac
+			conn.Write(server.seal(recordTypeAlert, []byte{2, 20}, false))
+			return
+		}
+
+		switch {
+		case typ == recordTypeHandshake && s.renegotiate:
+			conn.Write(server.seal(recordTypeHandshake, handshakeMessage(typeServerHello, sh), false))
+		case typ == recordTypeHandshake:
+			// no_renegotiation
+			conn.Write(server.seal(recordTypeAlert, []byte{1, 100}, false))
+		default:
+			conn.Write(server.seal(recordTypeApplicationData, []byte("HTTP/1.1 200 OK\r\n\r\n"), false))
+		}
+	}
+}
+
+func TestCBCHandshakeGoServer(t *testing.T) {
+	for _, version := range []uint16{uint16(etls.VersionTLS10), uint16(etls.VersionTLS12)} {
+		host, port := startFeatureServer(t, &tls.Config{
+			Certificates: []tls.Certificate{featureCertificate(t, false)},
+			CipherSuites: []uint16{etls.TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA},
+			MinVersion:   version,
+			MaxVersion:   version,
+		})
+
+		s, err := cbcHandshake(host, port, sessionCipherList(), DefaultOptions())
+		if err != nil {
+			t.Fatalf("handshake failed for %#04x: %v", version, err)
+		}
+		s.Close()
+
+		if s.version != version {
+			t.Errorf("wrong version, got: %#04x, want: %#04x", s.version, version)
+		}
+	}
+}
+
+func TestCBCHandshakeLowerVersion(t *testing.T) {
+	srv := newFakeSessionServer(t)
+	srv.maxVersion = etls.VersionTLS10
+	host, port := srv.start(t)
+
+	s, err := cbcHandshake(host, port, sessionCipherList(), DefaultOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	// the premaster secret carries the TLS 1.2 ClientHello version
+	if s.version != etls.VersionTLS10 || s.helloVersion != etls.VersionTLS12 {
+		t.Errorf("wrong versions, got: %#04x %#04x", s.version, s.helloVersion)
+	}
+}
+
+func TestCBCHandshakeNoServer(t *testing.T) {
+	_, err := cbcHandshake("127.0.0.1", "1", sessionCipherList(), DefaultOptions())
+	if err == nil {
+		t.Errorf("handshake should fail without a server")
+	}
+}
+
+func TestRecordCipherRoundTrip(t *testing.T) {
+	master := make([]byte, 48)
+	clientRandom := make([]byte, 32)
+	serverRandom := make([]byte, 32)
+
+	for _, version := range []uint16{uint16(etls.VersionTLS10), uint16(etls.VersionTLS11), uint16(etls.VersionTLS12)} {
+		seal, _ := newRecordCiphers(version, master, clientRandom, serverRandom, 32)
+		open, _ := newRecordCiphers(version, master, clientRandom, serverRandom, 32)
+
+		for _, data := range []string{"", "hello", "sixteen bytes!!!"} {
+			rec := seal.seal(recordTypeApplicationData, []byte(data), false)
+			got, err := open.open(recordTypeApplicationData, rec[5:], true)
+			if err != nil || string(got) != data {
+				t.Errorf("wrong data for %#04x, got: %q, %v, want: %q", version, got, err, data)
+			}
+		}
+
+		rec := seal.seal(recordTypeApplicationData, []byte("bad padding"), true)
+		if _, err := open.open(recordTypeApplicationData, rec[5:], true); err == nil {
+			t.Errorf("bad padding should be rejected for %#04x", version)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package ssl
+
+import (
+	"encoding/binary"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/jsandas/etls"
+	logger "github.com/jsandas/gologger"
+)
+
+// signatureSchemes are the signature algorithms tested by
+// SignatureAlgorithms. TLS 1.2 hash/signature pairs that were
+// not carried over to TLS 1.3 use the names from RFC 5246
+var signatureSchemes = map[uint16]string{
+	0x0101: "rsa_pkcs1_md5",
+	0x0201: "rsa_pkcs1_sha1",
+	0x0202: "dsa_sha1",
+	0x0203: "ecdsa_sha1",
+	0x0301: "rsa_pkcs1_sha224",
+	0x0302: "dsa_sha224",
+	0x0303: "ecdsa_sha224",
+	0x0401: "rsa_pkcs1_sha256",
+	0x0402: "dsa_sha256",
+	0x0403: "ecdsa_secp256r1_sha256",
+	0x0501: "rsa_pkcs1_sha384",
+	0x0503: "ecdsa_secp384r1_sha384",
+	0x0601: "rsa_pkcs1_sha512",
+	0x0603: "ecdsa_secp521r1_sha512",
+	0x0804: "rsa_pss_rsae_sha256",
+	0x0805: "rsa_pss_rsae_sha384",
+	0x0806: "rsa_pss_rsae_sha512",
+	0x0807: "ed25519",
+	0x0808: "ed448",
+	0x0809: "rsa_pss_pss_sha256",
+	0x080a: "rsa_pss_pss_sha384",
+	0x080b: "rsa_pss_pss_sha512",
+	0x081a: "ecdsa_brainpoolP256r1tls13_sha256",
+	0x081b: "ecdsa_brainpoolP384r1tls13_sha384",
+	0x081c: "ecdsa_brainpoolP512r1tls13_sha512",
+}
+
+// SignatureAlgorithm is a signature scheme accepted by the server
+type SignatureAlgorithm struct {
+	Name      string `json:"name"`
+	CodePoint string `json:"codePoint"`
+	// Weak is true for schemes using SHA-1 or MD5
+	Weak bool `json:"weak"`
+}
+
+// SignatureAlgorithms returns the signature schemes the server will use
+// to sign the handshake for TLS 1.2 (ServerKeyExchange) and TLS 1.3
+// (CertificateVerify)
+func SignatureAlgorithms(host string, port string, supported map[string][]string, opts Options) map[string][]SignatureAlgorithm {
+	var mutex = &sync.Mutex{}
+	var jobs []func()
+	found := make(map[int][]uint16)
+
+	for _, p := range []int{etls.VersionTLS12, etls.VersionTLS13} {
+		names, ok := supported[protocolVersionMap[p]]
+		if !ok {
+			continue
+		}
+
+		ciphers := signedCiphers(names, p)
+		if len(ciphers) == 0 {
+			logger.Debugf("event_id=no_signed_ciphers proto=%s", protocolVersionMap[p])
+			continue
+		}
+
+		for scheme := range signatureSchemes {
+			jobs = append(jobs, func() {
+				used, ok := signatureSchemeUsed(host, port, p, ciphers, scheme, opts)
+				if !ok {
+					return
+				}
+				mutex.Lock()
+				if !slices.Contains(found[p], used) {
+					found[p] = append(found[p], used)
+				}
+				mutex.Unlock()
+			})
+		}
+	}
+
+	runPool(opts.workers(), jobs)
+
+	results := make(map[string][]SignatureAlgorithm)
+	for p, schemes := range found {
+		slices.Sort(schemes)
+		for _, s := range schemes {
+			results[protocolVersionMap[p]] = append(results[protocolVersionMap[p]], newSignatureAlgorithm(s))
+		}
+	}
+
+	logger.Debugf("event_id=signature_algorithms results=%v", results)
+	return results
+}
+
+// newSignatureAlgorithm describes signature scheme s
+func newSignatureAlgorithm(s uint16) SignatureAlgorithm {
+	name, ok := signatureSchemes[s]
+	if !ok {
+		name = "unknown"
+	}
+
+	return SignatureAlgorithm{
+		Name:      name,
+		CodePoint: fmt.Sprintf("0x%04X", s),
+		Weak:      s>>8 == 1 || s>>8 == 2,
+	}
+}
+
+// signedCiphers returns the supported ciphers for which the server signs
+// the handshake. Only ephemeral, authenticated key exchanges have a
+// ServerKeyExchange signature in TLS 1.2
+func signedCiphers(names []string, p int) []uint16 {
+	var ciphers []uint16
+
+	for _, id := range cipherIDList(names) {
+		c := cipherSuites[id]
+		if p == etls.VersionTLS12 && (!isEphemeral(c, p) || c.authentication == "None") {
+			continue
+		}
+		ciphers = append(ciphers, id)
+	}
+
+	return ciphers
+}
+
+// signatureSchemeUsed offers only scheme and returns the scheme the server
+// signed the handshake with. For TLS 1.3 the signature is encrypted, so a
+// ServerHello is taken as acceptance of the offered scheme
+func signatureSchemeUsed(host string, port string, p int, ciphers []uint16, scheme uint16, opts Options) (uint16, bool) {
+	hello := newClientHello(host, p, ciphers)
+	hello.setExtension(extSignatureAlgorithms, uint16ListData([]uint16{scheme}))
+
+	if p == etls.VersionTLS13 {
+		flight, err := rawHandshake13(host, port, hello, opts)
+		if err != nil || flight.hello == nil || flight.hello.isHelloRetry() {
+			return 0, false
+		}
+		return scheme, true
+	}
+
+	hello.setGroups(kexGroups(p))
+
+	flight, err := rawHandshake(host, port, hello, opts)
+	if err != nil || flight.hello == nil {
+		return 0, false
+	}
+
+	kex := cipherSuites[flight.hello.cipher].keyExhange
+	return parseSignatureScheme(flight.keyExchange, strings.HasPrefix(kex, "ECDH"))
+}
+
+// parseSignatureScheme returns the signature algorithm of a TLS 1.2
+// ServerKeyExchange
+func parseSignatureScheme(ske []byte, ecdh bool) (uint16, bool) {
+	var offset int
+
+	if ecdh {
+		if _, ok := parseECParams(ske); !ok || len(ske) < 4 {
+			return 0, false
+		}
+		offset = 4 + int(ske[3])
+	} else {
+		p, g, ys, ok := parseDHParams(ske)
+		if !ok {
+			return 0, false
+		}
+		offset = 6 + len(p) + len(g) + len(ys)
+	}
+
+	if len(ske) < offset+2 {
+		return 0, false
+	}
+
+	return binary.BigEndian.Uint16(ske[offset : offset+2]), true
+}
@@ -0,0 +1,77 @@
+package ssl
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jsandas/etls"
+)
+
+func TestSignatureAlgorithms(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Write([]byte("Hello"))
+	}))
+	server.TLS = &tls.Config{
+		MinVersion: uint16(etls.VersionTLS12),
+		MaxVersion: uint16(etls.VersionTLS13),
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	host, port, _ := net.SplitHostPort(strings.Replace(server.URL, "https://", "", -1))
+
+	supported := map[string][]string{
+		"TLSv1.2": {"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"},
+		"TLSv1.3": {"TLS_AES_128_GCM_SHA256"},
+	}
+	results := SignatureAlgorithms(host, port, supported, DefaultOptions())
+
+	for p, want := range map[string][]string{
+		"TLSv1.2": {"rsa_pkcs1_sha256", "rsa_pkcs1_sha384", "rsa_pkcs1_sha512", "rsa_pss_rsae_sha256", "rsa_pss_rsae_sha384", "rsa_pss_rsae_sha512"},
+		"TLSv1.3": {"rsa_pss_rsae_sha256", "rsa_pss_rsae_sha384", "rsa_pss_rsae_sha512"},
+	} {
+		var got []string
+		for _, s := range results[p] {
+			got = append(got, s.Name)
+			if s.Weak {
+				t.Errorf("%s should not be weak", s.Name)
+			}
+		}
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("wrong signature algorithms for %s, got: %v, want: %v", p, got, want)
+		}
+	}
+}
+
+func TestSignedCiphers(t *testing.T) {
+	names := []string{"TLS_RSA_WITH_AES_128_CBC_SHA", "TLS_DH_anon_WITH_AES_128_CBC_SHA", "TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA"}
+
+	ciphers := signedCiphers(names, etls.VersionTLS12)
+	if len(ciphers) != 1 || ciphers[0] != etls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA {
+		t.Errorf("wrong signed ciphers, got: %v", ciphers)
+	}
+}
+
+func TestParseSignatureScheme(t *testing.T) {
+	ecdhe := []byte{3, 0, 23, 2, 0xaa, 0xbb, 0x02, 0x01, 0, 0}
+	if s, ok := parseSignatureScheme(ecdhe, true); !ok || s != 0x0201 {
+		t.Errorf("wrong ecdhe scheme, got: %#04x", s)
+	}
+
+	dhe := []byte{0, 1, 0xaa, 0, 1, 2, 0, 1, 0xcc, 0x08, 0x04, 0, 0}
+	if s, ok := parseSignatureScheme(dhe, false); !ok || s != 0x0804 {
+		t.Errorf("wrong dhe scheme, got: %#04x", s)
+	}
+
+	if _, ok := parseSignatureScheme([]byte{3, 0, 23}, true); ok {
+		t.Errorf("truncated key exchange should not parse")
+	}
+
+	if s := newSignatureAlgorithm(0x0201); !s.Weak || s.Name != "rsa_pkcs1_sha1" || s.CodePoint != "0x0201" {
+		t.Errorf("wrong signature algorithm, got: %+v", s)
+	}
+}
@@ -2,7 +2,6 @@ package ssl
 
 import (
 	"encoding/hex"
-	"net"
 	"time"
 
 	logger "github.com/jsandas/gologger"
@@ -43,19 +42,40 @@ func makeClientHello() []byte {
 }
 
 // Check check sslv2 support
-func sslv2Check(host string, port string) map[string][]string {
+func sslv2Check(host string, port string, opts Options) map[string][]string {
 	var connData = make(map[string][]string)
 
-	conn, err := net.DialTimeout("tcp", host+":"+port, 10*time.Second)
+	helloB := sslv2ServerHello(host, port, opts)
+	if helloB == nil {
+		return connData
+	}
+
+	ciphers := sslv2GetCiphers(helloB)
+
+	if len(ciphers) == 0 {
+		return connData
+	}
+
+	connData["SSLv2"] = ciphers
+
+	logger.Debugf("event_id=sslv2_results msg=\"%v\"", connData)
+
+	return connData
+}
+
+// sslv2ServerHello sends an SSLv2 ClientHello and returns the server's
+// response if it is an SSLv2 ServerHello
+func sslv2ServerHello(host string, port string, opts Options) []byte {
+	conn, err := opts.dial(host, port, 10*time.Second)
 	if err != nil {
 		logger.Errorf("event_id=tcp_dial_failed msg\"%v\"", err)
-		return connData
+		return nil
 	}
 	defer conn.Close()
 
-	err = StartTLS(conn, port)
+	err = StartTLSWithOptions(conn, host, port, opts.StartTLS)
 	if err != nil {
-		return connData
+		return nil
 	}
 
 	// Send clientHello
@@ -64,31 +84,31 @@ func sslv2Check(host string, port string) map[string][]string {
 	err = tcputils.Write(conn, clientHello, 2)
 	if err != nil {
 		logger.Errorf("event_id=clientHello_send_failed msg\"%v\"", err)
-		return connData
+		return nil
 	}
 
 	helloB, err := tcputils.Read(conn, 2)
 	if err != nil {
 		logger.Errorf("event_id=serverHello_read_failed msg\"%v\"", err)
-		return connData
+		return nil
 	}
 
 	if len(helloB) < 200 {
 		logger.Debugf("event_id=serverHello_not_found")
-		return connData
+		return nil
 	}
 
-	ciphers := sslv2GetCiphers(helloB)
+	return helloB
+}
 
-	if len(ciphers) == 0 {
-		return connData
+// sslv2GetCertificate returns the DER certificate of an SSLv2 ServerHello
+func sslv2GetCertificate(bytes []byte) []byte {
+	certLength := utils.BytetoInt(bytes[7:9])
+	if len(bytes) < 13+certLength {
+		return nil
 	}
 
-	connData["SSLv2"] = ciphers
-
-	logger.Debugf("event_id=sslv2_results msg=\"%v\"", connData)
-
-	return connData
+	return bytes[13 : 13+certLength]
 }
 
 func sslv2GetCiphers(bytes []byte) []string {
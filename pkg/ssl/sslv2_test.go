@@ -46,7 +46,7 @@ func TestCheckNoSSLv2(t *testing.T) {
 	s := strings.Split(strings.Replace(server.URL, "https://", "", -1), ":")
 	host := s[0]
 	port := s[1]
-	r := sslv2Check(host, port)
+	r := sslv2Check(host, port, DefaultOptions())
 	if _, ok := r["SSLv2"]; ok {
 		t.Errorf("sslv2 check should have failed, host: %s:%s", host, port)
 	}
@@ -72,7 +72,7 @@ func TestCheckSSLv2(t *testing.T) {
 	s := strings.Split(strings.Replace(srv.Addr().String(), "https://", "", -1), ":")
 	host := s[0]
 	port := s[1]
-	r := sslv2Check(host, port)
+	r := sslv2Check(host, port, DefaultOptions())
 	if _, ok := r["SSLv2"]; !ok {
 		t.Errorf("sslv2 check should have succeeded, host: %s:%s", host, port)
 	}
@@ -82,7 +82,7 @@ func TestCheckSSLv2(t *testing.T) {
 func TestCheckError(t *testing.T) {
 	var host string = "test.local"
 	var port string = "443"
-	r := sslv2Check(host, port)
+	r := sslv2Check(host, port, DefaultOptions())
 	if _, ok := r["SSLv2"]; ok {
 		t.Errorf("sslv2 check should have failed, host: %s:%s", host, port)
 	}
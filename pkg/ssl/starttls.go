@@ -11,6 +11,9 @@ import (
 	"github.com/jsandas/tlstools/pkg/utils"
 )
 
+// errStartTLSNotSupported is returned when the server does not offer TLS
+var errStartTLSNotSupported = errors.New("starttls_not_supported")
+
 type startTLSmsg struct {
 	protocol string
 	greetMSG string
@@ -83,20 +86,67 @@ func (s *startTLSmsg) connect(w *bufio.Writer, r *bufio.Reader) (err error) {
 	rgx = regexp.MustCompile(s.respMSG)
 	if !rgx.MatchString(line) {
 		logger.Debugf("event_id=starttls_not_supported server=%s line=%s msg=\"%v\"", s.protocol, strings.TrimSpace(line), err)
-		return errors.New("starttls_not_supported")
+		return errStartTLSNotSupported
 	}
 
 	return
 }
 
-// StartTLS for non-http servers
+// StartTLSOptions configures the protocol specific StartTLS negotiation
+type StartTLSOptions struct {
+	// Service is the protocol spoken before the TLS handshake (see
+	// utils.GetService), it is detected from the port when empty
+	Service string
+	// XMPPDomain is the domain sent in the XMPP stream header, the host
+	// name is used when empty
+	XMPPDomain string
+	// PostgresDirectTLS skips the SSLRequest and offers the postgresql
+	// ALPN protocol in every handshake, for servers only accepting direct
+	// TLS connections
+	PostgresDirectTLS bool
+}
+
+// alpn returns the ALPN protocols every handshake has to offer
+func (o StartTLSOptions) alpn() []string {
+	if o.PostgresDirectTLS {
+		return []string{PostgresALPN}
+	}
+	return nil
+}
+
+// ServiceFor returns the protocol spoken on port before the TLS handshake
+func (o StartTLSOptions) ServiceFor(port string) string {
+	if o.Service != "" {
+		return o.Service
+	}
+	return utils.GetService(port)
+}
+
+// xmppDomain returns the XMPP stream domain for host
+func (o StartTLSOptions) xmppDomain(host string) string {
+	if o.XMPPDomain != "" {
+		return o.XMPPDomain
+	}
+	return host
+}
+
+// StartTLS for non-http servers. The host name is not known here, so XMPP
+// streams are opened with the IP address of conn as the domain, which most
+// servers reject. Use StartTLSWithOptions for XMPP
 func StartTLS(conn net.Conn, port string) (err error) {
+	host, _, _ := net.SplitHostPort(conn.RemoteAddr().String())
+
+	return StartTLSWithOptions(conn, host, port, StartTLSOptions{})
+}
+
+// StartTLSWithOptions for non-http servers on host
+func StartTLSWithOptions(conn net.Conn, host string, port string, opts StartTLSOptions) (err error) {
 	r := bufio.NewReader(conn)
 	w := bufio.NewWriter(conn)
 
-	proto := utils.GetService(port)
+	proto := opts.ServiceFor(port)
 
-	// "https", "imapSSL", "pop3SSL", "rdp", "smtpSSL" use regular TLS connections
+	// "https", "imapSSL", "ldapSSL", "pop3SSL", "smtpSSL" use regular TLS connections
 	// and are not processed further
 	switch proto {
 	case "ftp":
@@ -131,6 +181,18 @@ func StartTLS(conn net.Conn, port string) (err error) {
 			respMSG:  "^220 ",
 		}
 		err = msg.connect(w, r)
+	case "mysql":
+		err = mysqlStartTLS(conn)
+	case "postgres":
+		if !opts.PostgresDirectTLS {
+			err = postgresStartTLS(conn)
+		}
+	case "ldap":
+		err = ldapStartTLS(conn)
+	case "rdp":
+		err = rdpStartTLS(conn)
+	case "xmpp", "xmpp-server":
+		_, err = xmppStartTLS(conn, proto, opts.xmppDomain(host))
 	}
 
 	return
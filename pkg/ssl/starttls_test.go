@@ -1,6 +1,7 @@
 package ssl
 
 import (
+	"crypto/tls"
 	"fmt"
 	"log"
 	"net"
@@ -8,6 +9,43 @@ import (
 	"time"
 )
 
+// startServiceServer runs a fake server on a free port calling handle for
+// each connection, cfg holds the certificate for the TLS handshake after
+// the StartTLS negotiation. The service is selected with serviceOptions
+func startServiceServer(t *testing.T, handle func(conn net.Conn, cfg *tls.Config)) (string, string) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	cfg := &tls.Config{Certificates: []tls.Certificate{featureCertificate(t, false)}}
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				conn.SetDeadline(time.Now().Add(5 * time.Second))
+				handle(conn, cfg)
+			}()
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	return host, port
+}
+
+// serviceOptions returns the default options for a server of service
+func serviceOptions(service string) Options {
+	opts := DefaultOptions()
+	opts.StartTLS.Service = service
+	return opts
+}
+
 type testServerData struct {
 	port     string
 	greetMSG string
@@ -25,10 +63,8 @@ func testServer(msg testServerData) error {
 		return err
 	}
 
-	var srvConn net.Conn
-
 	go func() {
-		srvConn, err = srv.Accept()
+		srvConn, err := srv.Accept()
 		if err != nil {
 			fmt.Println(err)
 			return
@@ -98,3 +134,12 @@ func TestSTARTTLS(t *testing.T) {
 		}
 	}
 }
+
+func TestStartTLSOptionsServiceFor(t *testing.T) {
+	if s := (StartTLSOptions{}).ServiceFor("5432"); s != "postgres" {
+		t.Errorf("service should be detected from the port, got: %s", s)
+	}
+	if s := (StartTLSOptions{Service: "ldap"}).ServiceFor("10389"); s != "ldap" {
+		t.Errorf("service should be overridden, got: %s", s)
+	}
+}
@@ -0,0 +1,171 @@
+package ssl
+
+import (
+	"bytes"
+	"encoding/hex"
+	"time"
+
+	"github.com/jsandas/etls"
+	logger "github.com/jsandas/gologger"
+)
+
+// ticketbleedSessionID is the 1 byte session id sent with the ticket
+var ticketbleedSessionID = []byte{0x42}
+
+// ticketRotationConnections is the number of tickets compared by
+// TicketKeyRotation
+const ticketRotationConnections = 3
+
+// ticketKeyNameLen is the length of the key_name of the ticket format
+// recommended by RFC 5077
+const ticketKeyNameLen = 16
+
+// ticket key rotation results
+const (
+	RotationStatic  = "static"
+	RotationRotated = "rotated"
+	RotationUnknown = "unknown"
+)
+
+// Ticketbleed checks if the server leaks memory in the session id when
+// resuming a session ticket (CVE-2016-9244)
+type Ticketbleed struct {
+	Vulnerable string `json:"vulnerable"`
+	// LeakedBytes is the number of bytes echoed beyond the session id
+	LeakedBytes int `json:"leakedBytes"`
+}
+
+// Check obtains a ticket and resumes it with a 1 byte session id. An
+// affected server echoes a 32 byte session id, of which 31 bytes are
+// uninitialized memory. Only resumed sessions are considered, the random
+// session id of a full handshake may start with the same byte. The
+// supported protocols and ciphers are those found by Check
+func (v *Ticketbleed) Check(host string, port string, supported map[string][]string, opts Options) error {
+	v.LeakedBytes = 0
+
+	p, ciphers, _, ticket := legacyTicket(host, port, supported, opts)
+	if ticket == nil {
+		v.Vulnerable = notApplicable
+		return nil
+	}
+
+	conn, err := rawDial(host, port, opts)
+	if err != nil {
+		v.Vulnerable = testFailed
+		return err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	hello := newClientHello(host, p, ciphers)
+	hello.setGroups(kexGroups(p))
+	hello.sessionID = ticketbleedSessionID
+	hello.setExtension(extSessionTicket, ticket)
+	// servers refuse to resume a session created with the extended master
+	// secret without it
+	hello.setExtension(extExtendedMasterSecret, []byte{})
+	hello.setStartTLS(opts.StartTLS)
+
+	if _, err := conn.Write(hello.marshal()); err != nil {
+		v.Vulnerable = testFailed
+		return err
+	}
+
+	hr := &handshakeReader{r: conn}
+	typ, body, err := hr.next()
+	if err != nil || typ != typeServerHello {
+		v.Vulnerable = testFailed
+		return err
+	}
+
+	sh, err := parseServerHello(body)
+	if err != nil {
+		v.Vulnerable = testFailed
+		return err
+	}
+
+	v.Vulnerable = notVulnerable
+	if len(sh.sessionID) > len(ticketbleedSessionID) && bytes.HasPrefix(sh.sessionID, ticketbleedSessionID) && abbreviatedHandshake(conn, hr) {
+		v.LeakedBytes = len(sh.sessionID) - len(ticketbleedSessionID)
+		v.Vulnerable = vulnerable
+		logger.Debugf("event_id=ticketbleed leaked=%x", sh.sessionID[len(ticketbleedSessionID):])
+	}
+
+	return nil
+}
+
+// legacyTicket returns a session ticket and its lifetime hint for the
+// highest supported protocol up to TLS 1.2 that issues one, offering the
+// ciphers supported with that protocol
+func legacyTicket(host string, port string, supported map[string][]string, opts Options) (int, []uint16, uint32, []byte) {
+	for _, p := range []int{etls.VersionTLS12, etls.VersionTLS11, etls.VersionTLS10} {
+		ciphers := cipherIDList(supported[protocolVersionMap[p]])
+		if len(ciphers) == 0 {
+			continue
+		}
+		if lifetime, ticket := newSessionTicket(host, port, p, ciphers, opts); ticket != nil {
+			return p, ciphers, lifetime, ticket
+		}
+	}
+	return 0, nil, 0, nil
+}
+
+// TicketKeyRotation compares the tickets issued on several connections.
+// Tickets in the RFC 5077 format start with the name of the key that
+// encrypted them
+type TicketKeyRotation struct {
+	// Rotation is static when every ticket has the same key name, rotated
+	// when the key name changed between connections and unknown when no
+	// two tickets share a key name
+	Rotation string `json:"rotation"`
+	Tickets  int    `json:"tickets"`
+	// KeyNames are the distinct key names in the order they were seen
+	KeyNames []string `json:"keyNames,omitempty"`
+	// LifetimeHint is the ticket lifetime in seconds
+	LifetimeHint uint32 `json:"lifetimeHint"`
+}
+
+// Check requests a ticket on several connections with the first
+// supported protocol and ciphers (see legacyTicket) that issue one
+func (r *TicketKeyRotation) Check(host string, port string, supported map[string][]string, opts Options) {
+	*r = TicketKeyRotation{}
+
+	var names []string
+	p, ciphers, lifetime, ticket := legacyTicket(host, port, supported, opts)
+	for i := 0; ticket != nil && i < ticketRotationConnections; i++ {
+		if i > 0 {
+			lifetime, ticket = newSessionTicket(host, port, p, ciphers, opts)
+		}
+		if len(ticket) < ticketKeyNameLen {
+			continue
+		}
+
+		r.Tickets++
+		r.LifetimeHint = lifetime
+		names = append(names, hex.EncodeToString(ticket[:ticketKeyNameLen]))
+	}
+
+	r.Rotation, r.KeyNames = ticketRotation(names)
+
+	logger.Debugf("event_id=ticket_key_rotation rotation=%s tickets=%d", r.Rotation, r.Tickets)
+}
+
+// ticketRotation classifies the key names of consecutive tickets and
+// returns the distinct names in the order they were seen
+func ticketRotation(names []string) (string, []string) {
+	var distinct []string
+	for _, n := range names {
+		if len(distinct) == 0 || distinct[len(distinct)-1] != n {
+			distinct = append(distinct, n)
+		}
+	}
+
+	switch {
+	case len(names) < 2 || len(distinct) == len(names):
+		return RotationUnknown, nil
+	case len(distinct) == 1:
+		return RotationStatic, distinct
+	default:
+		return RotationRotated, distinct
+	}
+}
@@ -0,0 +1,232 @@
+package ssl
+
+import (
+	"crypto/tls"
+	"encoding/binary"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jsandas/etls"
+)
+
+// startTicketbleedServer runs a Go TLS server behind a proxy that answers
+// ClientHellos offering a ticket like an affected server: the ServerHello
+// echoes the client's session id padded to 32 bytes, followed by
+// ChangeCipherSpec. Without resume the ServerHello starts a full handshake
+// instead. Other connections are forwarded to the Go server
+func startTicketbleedServer(t *testing.T, resume bool) (string, string) {
+	_, backend := startFeatureServer(t, &tls.Config{
+		Certificates: []tls.Certificate{featureCertificate(t, false)},
+		MaxVersion:   uint16(etls.VersionTLS12),
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go handleTicketbleed(conn, backend, resume)
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	return host, port
+}
+
+func handleTicketbleed(conn net.Conn, backend string, resume bool) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	typ, version, fragment, err := readRecord(conn)
+	if err != nil || typ != recordTypeHandshake || len(fragment) < 4 {
+		return
+	}
+
+	body := fragment[4:]
+	if len(testClientHelloExtension(body, extSessionTicket)) > 0 {
+		sessionID, cipher, _ := parseTestClientHello(body)
+		leaked := append(sessionID, make([]byte, 32-len(sessionID))...)
+
+		s := &fakeFeatureServer{}
+		conn.Write(record(recordTypeHandshake, etls.VersionTLS12, s.serverHello(leaked, cipher, nil)))
+		if resume {
+			conn.Write(record(recordTypeChangeCipherSpec, etls.VersionTLS12, []byte{1}))
+		} else {
+			conn.Write(record(recordTypeHandshake, etls.VersionTLS12, handshakeMessage(typeCertificate, []byte{0, 0, 0})))
+		}
+		return
+	}
+
+	b, err := net.Dial("tcp", net.JoinHostPort("127.0.0.1", backend))
+	if err != nil {
+		return
+	}
+	defer b.Close()
+
+	b.Write(record(recordTypeHandshake, version, fragment))
+	go io.Copy(b, conn)
+	io.Copy(conn, b)
+}
+
+// testClientHelloExtension returns the data of extension id of a
+// ClientHello body
+func testClientHelloExtension(b []byte, id uint16) []byte {
+	b = b[34:]
+	b = b[1+int(b[0]):]
+	b = b[2+int(binary.BigEndian.Uint16(b)):]
+	b = b[1+int(b[0]):]
+
+	if len(b) < 2 {
+		return nil
+	}
+	b = b[2:]
+	for len(b) >= 4 {
+		l := int(binary.BigEndian.Uint16(b[2:4]))
+		if binary.BigEndian.Uint16(b) == id {
+			return b[4 : 4+l]
+		}
+		b = b[4+l:]
+	}
+
+	return nil
+}
+
+func TestTicketbleedGoServer(t *testing.T) {
+	host, port := startFeatureServer(t, &tls.Config{
+		Certificates: []tls.Certificate{featureCertificate(t, false)},
+		MaxVersion:   uint16(etls.VersionTLS12),
+	})
+
+	var v Ticketbleed
+	if err := v.Check(host, port, featureCiphers, DefaultOptions()); err != nil {
+		t.Fatal(err)
+	}
+	if v.Vulnerable != notVulnerable || v.LeakedBytes != 0 {
+		t.Errorf("wrong result, got: %+v", v)
+	}
+}
+
+func TestTicketbleedFakeServer(t *testing.T) {
+	host, port := startTicketbleedServer(t, true)
+
+	var v Ticketbleed
+	if err := v.Check(host, port, featureCiphers, DefaultOptions()); err != nil {
+		t.Fatal(err)
+	}
+	if v.Vulnerable != vulnerable || v.LeakedBytes != 31 {
+		t.Errorf("wrong result, got: %+v", v)
+	}
+}
+
+func TestTicketbleedFullHandshake(t *testing.T) {
+	// a new session id starting with the probe byte is not a leak
+	host, port := startTicketbleedServer(t, false)
+
+	var v Ticketbleed
+	if err := v.Check(host, port, featureCiphers, DefaultOptions()); err != nil {
+		t.Fatal(err)
+	}
+	if v.Vulnerable != notVulnerable || v.LeakedBytes != 0 {
+		t.Errorf("wrong result, got: %+v", v)
+	}
+}
+
+func TestTicketbleedNoTickets(t *testing.T) {
+	host, port := startFeatureServer(t, &tls.Config{
+		Certificates:           []tls.Certificate{featureCertificate(t, false)},
+		SessionTicketsDisabled: true,
+	})
+
+	var v Ticketbleed
+	if err := v.Check(host, port, featureCiphers, DefaultOptions()); err != nil {
+		t.Fatal(err)
+	}
+	if v.Vulnerable != notApplicable {
+		t.Errorf("wrong result, got: %+v", v)
+	}
+}
+
+func TestTicketKeyRotationGoServer(t *testing.T) {
+	host, port := startFeatureServer(t, &tls.Config{
+		Certificates: []tls.Certificate{featureCertificate(t, false)},
+		MaxVersion:   uint16(etls.VersionTLS12),
+	})
+
+	var r TicketKeyRotation
+	r.Check(host, port, featureCiphers, DefaultOptions())
+
+	// go tickets start with a random iv instead of a key name
+	if r.Rotation != RotationUnknown || r.Tickets != ticketRotationConnections || r.KeyNames != nil {
+		t.Errorf("wrong result, got: %+v", r)
+	}
+}
+
+func TestTicketbleedLegacyCiphers(t *testing.T) {
+	// neither cipher is in the default suites of crypto/tls
+	ciphers := []uint16{etls.TLS_RSA_WITH_AES_128_CBC_SHA, etls.TLS_RSA_WITH_3DES_EDE_CBC_SHA}
+	host, port := startFeatureServer(t, &tls.Config{
+		Certificates: []tls.Certificate{rsaCertificate(t, testRSAKey(t))},
+		MinVersion:   uint16(etls.VersionTLS10),
+		MaxVersion:   uint16(etls.VersionTLS11),
+		CipherSuites: ciphers,
+	})
+	supported := map[string][]string{"TLSv1.1": cipherStrList(ciphers), "TLSv1.0": cipherStrList(ciphers)}
+
+	var v Ticketbleed
+	if err := v.Check(host, port, supported, DefaultOptions()); err != nil {
+		t.Fatal(err)
+	}
+	if v.Vulnerable != notVulnerable {
+		t.Errorf("wrong result, got: %+v", v)
+	}
+
+	var r TicketKeyRotation
+	r.Check(host, port, supported, DefaultOptions())
+	if r.Tickets != ticketRotationConnections {
+		t.Errorf("wrong result, got: %+v", r)
+	}
+}
+
+func TestTicketKeyRotationReset(t *testing.T) {
+	host, port := startFeatureServer(t, &tls.Config{
+		Certificates:           []tls.Certificate{featureCertificate(t, false)},
+		SessionTicketsDisabled: true,
+	})
+
+	r := TicketKeyRotation{Rotation: RotationStatic, Tickets: 3, KeyNames: []string{"aa"}, LifetimeHint: 7200}
+	r.Check(host, port, featureCiphers, DefaultOptions())
+	if !reflect.DeepEqual(r, TicketKeyRotation{Rotation: RotationUnknown}) {
+		t.Errorf("previous result should be reset, got: %+v", r)
+	}
+}
+
+func TestTicketRotation(t *testing.T) {
+	var tests = []struct {
+		names    []string
+		rotation string
+		keyNames []string
+	}{
+		{[]string{"aa", "aa", "aa"}, RotationStatic, []string{"aa"}},
+		{[]string{"aa", "aa", "bb"}, RotationRotated, []string{"aa", "bb"}},
+		{[]string{"aa", "bb", "cc"}, RotationUnknown, nil},
+		{[]string{"aa"}, RotationUnknown, nil},
+		{nil, RotationUnknown, nil},
+	}
+
+	for _, test := range tests {
+		rotation, keyNames := ticketRotation(test.names)
+		if rotation != test.rotation || !reflect.DeepEqual(keyNames, test.keyNames) {
+			t.Errorf("%v: got %s %v, expected %s %v", test.names, rotation, keyNames, test.rotation, test.keyNames)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package ssl
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	logger "github.com/jsandas/gologger"
+)
+
+// weakDHSize is the smallest DH prime that is not reported as weak
+const weakDHSize = 2048
+
+// logjamDHSize is the largest DH prime that is reported as Logjam
+const logjamDHSize = 1024
+
+// FREAK checks if RSA export ciphers are accepted (CVE-2015-0204)
+type FREAK struct {
+	Vulnerable string `json:"vulnerable"`
+	// Evidence lists the accepted export ciphers
+	Evidence []string `json:"evidence,omitempty"`
+}
+
+// Check derives the verdict from the supported ciphers
+func (v *FREAK) Check(supported map[string][]CipherResult) {
+	v.Evidence = nil
+	eachCipher(supported, func(p string, c CipherResult) {
+		if strings.HasPrefix(c.KeyExchange, "RSA(") {
+			v.Evidence = append(v.Evidence, fmt.Sprintf("%s %s", p, c.Name))
+		}
+	})
+
+	v.Vulnerable = verdict(v.Evidence)
+	logger.Debugf("event_id=freak vulnerable=%s evidence=%v", v.Vulnerable, v.Evidence)
+}
+
+// Logjam checks if DHE export ciphers are accepted or the server uses a
+// DH prime of at most 1024 bits (CVE-2015-4000)
+type Logjam struct {
+	Vulnerable string `json:"vulnerable"`
+	// Evidence lists the export ciphers and the weak DH primes
+	Evidence []string `json:"evidence,omitempty"`
+}
+
+// Check derives the verdict from the supported ciphers and the
+// ServerKeyExchange parameters recorded with them
+func (v *Logjam) Check(supported map[string][]CipherResult) {
+	v.Evidence = nil
+	eachCipher(supported, func(p string, c CipherResult) {
+		kex := c.KeyExchangeParams
+		switch {
+		case strings.HasPrefix(c.KeyExchange, "DHE(") || strings.HasPrefix(c.KeyExchange, "DH("):
+			v.Evidence = append(v.Evidence, fmt.Sprintf("%s %s export", p, c.Name)+dhEvidence(kex))
+		case kex != nil && kex.Type == kexDH && (kex.Size <= logjamDHSize || kex.Logjam):
+			v.Evidence = append(v.Evidence, fmt.Sprintf("%s %s", p, c.Name)+dhEvidence(kex))
+		}
+	})
+
+	v.Vulnerable = verdict(v.Evidence)
+	logger.Debugf("event_id=logjam vulnerable=%s evidence=%v", v.Vulnerable, v.Evidence)
+}
+
+// WeakDH warns about DH primes smaller than 2048 bits
+type WeakDH struct {
+	Vulnerable string `json:"vulnerable"`
+	// MinSize is the smallest DH prime used by the server
+	MinSize int `json:"minSize,omitempty"`
+	// Evidence lists the ciphers using a weak prime
+	Evidence []string `json:"evidence,omitempty"`
+}
+
+// Check derives the verdict from the ServerKeyExchange parameters. It is
+// not applicable when the server never used a DH key exchange
+func (v *WeakDH) Check(supported map[string][]CipherResult) {
+	v.Evidence = nil
+	v.MinSize = 0
+	eachCipher(supported, func(p string, c CipherResult) {
+		kex := c.KeyExchangeParams
+		if kex == nil || kex.Type != kexDH {
+			return
+		}
+		if v.MinSize == 0 || kex.Size < v.MinSize {
+			v.MinSize = kex.Size
+		}
+		if kex.Size < weakDHSize {
+			v.Evidence = append(v.Evidence, fmt.Sprintf("%s %s", p, c.Name)+dhEvidence(kex))
+		}
+	})
+
+	v.Vulnerable = verdict(v.Evidence)
+	if v.MinSize == 0 {
+		v.Vulnerable = notApplicable
+	}
+	logger.Debugf("event_id=weak_dh vulnerable=%s min_size=%d", v.Vulnerable, v.MinSize)
+}
+
+// eachCipher calls fn for the supported ciphers ordered by protocol
+func eachCipher(supported map[string][]CipherResult, fn func(p string, c CipherResult)) {
+	for _, p := range slices.Sorted(maps.Keys(supported)) {
+		for _, c := range supported[p] {
+			fn(p, c)
+		}
+	}
+}
+
+// dhEvidence describes a DH prime, e.g. " (1024 bits, rfc2409-1024)"
+func dhEvidence(kex *KeyExchange) string {
+	if kex == nil || kex.Type != kexDH {
+		return ""
+	}
+	if kex.Group != "" {
+		return fmt.Sprintf(" (%d bits, %s)", kex.Size, kex.Group)
+	}
+	return fmt.Sprintf(" (%d bits)", kex.Size)
+}
+
+// verdict is vulnerable when there is evidence
+func verdict(evidence []string) string {
+	if len(evidence) > 0 {
+		return vulnerable
+	}
+	return notVulnerable
+}
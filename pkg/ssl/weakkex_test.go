@@ -0,0 +1,101 @@
+package ssl
+
+import (
+	"reflect"
+	"testing"
+)
+
+// weakKexConfig returns a supported config using the named ciphers with
+// the given key exchange parameters
+func weakKexConfig(ciphers map[string]*KeyExchange) map[string][]CipherResult {
+	var results []CipherResult
+	for _, name := range []string{
+		"TLS_RSA_EXPORT_WITH_RC4_40_MD5",
+		"TLS_DHE_RSA_EXPORT_WITH_DES40_CBC_SHA",
+		"TLS_DHE_RSA_WITH_AES_128_CBC_SHA",
+		"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+	} {
+		kex, ok := ciphers[name]
+		if !ok {
+			continue
+		}
+		r := newCipherResult(name)
+		r.KeyExchangeParams = kex
+		results = append(results, r)
+	}
+	return map[string][]CipherResult{"TLSv1.0": results}
+}
+
+func TestFREAK(t *testing.T) {
+	var v FREAK
+
+	v.Check(weakKexConfig(map[string]*KeyExchange{"TLS_RSA_EXPORT_WITH_RC4_40_MD5": nil}))
+	if v.Vulnerable != vulnerable || !reflect.DeepEqual(v.Evidence, []string{"TLSv1.0 TLS_RSA_EXPORT_WITH_RC4_40_MD5"}) {
+		t.Errorf("wrong result, got: %+v", v)
+	}
+
+	v.Check(weakKexConfig(map[string]*KeyExchange{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256": {Type: kexECDH, Size: 256}}))
+	if v.Vulnerable != notVulnerable || len(v.Evidence) != 0 {
+		t.Errorf("wrong result, got: %+v", v)
+	}
+}
+
+func TestLogjam(t *testing.T) {
+	tests := []struct {
+		name     string
+		ciphers  map[string]*KeyExchange
+		evidence []string
+	}{
+		{
+			name:     "export",
+			ciphers:  map[string]*KeyExchange{"TLS_DHE_RSA_EXPORT_WITH_DES40_CBC_SHA": {Type: kexDH, Size: 512}},
+			evidence: []string{"TLSv1.0 TLS_DHE_RSA_EXPORT_WITH_DES40_CBC_SHA export (512 bits)"},
+		},
+		{
+			name:     "common1024",
+			ciphers:  map[string]*KeyExchange{"TLS_DHE_RSA_WITH_AES_128_CBC_SHA": {Type: kexDH, Size: 1024, Group: "rfc2409-1024", CommonPrime: true, Logjam: true}},
+			evidence: []string{"TLSv1.0 TLS_DHE_RSA_WITH_AES_128_CBC_SHA (1024 bits, rfc2409-1024)"},
+		},
+		{
+			name:     "custom1024",
+			ciphers:  map[string]*KeyExchange{"TLS_DHE_RSA_WITH_AES_128_CBC_SHA": {Type: kexDH, Size: 1024}},
+			evidence: []string{"TLSv1.0 TLS_DHE_RSA_WITH_AES_128_CBC_SHA (1024 bits)"},
+		},
+		{
+			name:    "common2048",
+			ciphers: map[string]*KeyExchange{"TLS_DHE_RSA_WITH_AES_128_CBC_SHA": {Type: kexDH, Size: 2048, Group: "rfc3526-2048", CommonPrime: true}},
+		},
+	}
+
+	for _, tt := range tests {
+		var v Logjam
+		v.Check(weakKexConfig(tt.ciphers))
+
+		want := verdict(tt.evidence)
+		if v.Vulnerable != want || !reflect.DeepEqual(v.Evidence, tt.evidence) {
+			t.Errorf("%s: wrong result, got: %+v, want: %s %v", tt.name, v, want, tt.evidence)
+		}
+	}
+}
+
+func TestWeakDH(t *testing.T) {
+	var v WeakDH
+
+	v.Check(weakKexConfig(map[string]*KeyExchange{
+		"TLS_DHE_RSA_EXPORT_WITH_DES40_CBC_SHA": {Type: kexDH, Size: 512},
+		"TLS_DHE_RSA_WITH_AES_128_CBC_SHA":      {Type: kexDH, Size: 2048},
+	}))
+	if v.Vulnerable != vulnerable || v.MinSize != 512 || len(v.Evidence) != 1 {
+		t.Errorf("wrong result, got: %+v", v)
+	}
+
+	v.Check(weakKexConfig(map[string]*KeyExchange{"TLS_DHE_RSA_WITH_AES_128_CBC_SHA": {Type: kexDH, Size: 3072}}))
+	if v.Vulnerable != notVulnerable || v.MinSize != 3072 {
+		t.Errorf("wrong result, got: %+v", v)
+	}
+
+	v.Check(weakKexConfig(map[string]*KeyExchange{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256": {Type: kexECDH, Size: 256}}))
+	if v.Vulnerable != notApplicable {
+		t.Errorf("wrong result, got: %+v", v)
+	}
+}
@@ -0,0 +1,143 @@
+package ssl
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	logger "github.com/jsandas/gologger"
+)
+
+// XMPP namespaces
+const (
+	xmppStreamsNS = "http://etherx.jabber.org/streams"
+	xmppTLSNS     = "urn:ietf:params:xml:ns:xmpp-tls"
+	xmppClientNS  = "jabber:client"
+	xmppServerNS  = "jabber:server"
+)
+
+// xmppFeatures is the stream features element
+type xmppFeatures struct {
+	StartTLS *struct {
+		Required *struct{} `xml:"required"`
+	} `xml:"urn:ietf:params:xml:ns:xmpp-tls starttls"`
+}
+
+// xmppStreamError is the stream error element, the condition is the
+// name of its child
+type xmppStreamError struct {
+	Condition struct {
+		XMLName xml.Name
+	} `xml:",any"`
+}
+
+// byteReader reads one byte at a time so that the xml decoder does not
+// consume the start of the TLS stream
+type byteReader struct {
+	r io.Reader
+}
+
+func (b byteReader) Read(p []byte) (int, error) {
+	return b.r.Read(p)
+}
+
+func (b byteReader) ReadByte() (byte, error) {
+	var c [1]byte
+	_, err := io.ReadFull(b.r, c[:])
+	return c[0], err
+}
+
+// xmppStreamHeader returns the opening stream tag for service
+func xmppStreamHeader(service string, domain string) string {
+	ns := xmppClientNS
+	if service == "xmpp-server" {
+		ns = xmppServerNS
+	}
+
+	return fmt.Sprintf("<?xml version='1.0'?><stream:stream xmlns='%s' xmlns:stream='%s' to='%s' version='1.0'>", ns, xmppStreamsNS, domain)
+}
+
+// xmppStartTLS opens an XMPP stream to domain, and requests TLS if it is
+// offered in the stream features. It returns if the server marks TLS as
+// required
+func xmppStartTLS(conn net.Conn, service string, domain string) (required bool, err error) {
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	defer conn.SetDeadline(time.Time{})
+
+	if _, err = io.WriteString(conn, xmppStreamHeader(service, domain)); err != nil {
+		return
+	}
+
+	d := xml.NewDecoder(byteReader{r: conn})
+
+	var f xmppFeatures
+	if err = xmppNextElement(d, xmppStreamsNS, "features", &f); err != nil {
+		logger.Debugf("event_id=xmpp_features_failed server=%s domain=%s msg=\"%v\"", service, domain, err)
+		return
+	}
+
+	if f.StartTLS == nil {
+		logger.Debugf("event_id=starttls_not_supported server=%s domain=%s", service, domain)
+		return false, errStartTLSNotSupported
+	}
+	required = f.StartTLS.Required != nil
+
+	if _, err = io.WriteString(conn, "<starttls xmlns='"+xmppTLSNS+"'/>"); err != nil {
+		return
+	}
+
+	if err = xmppNextElement(d, xmppTLSNS, "proceed", nil); err != nil {
+		logger.Debugf("event_id=xmpp_starttls_failed server=%s domain=%s msg=\"%v\"", service, domain, err)
+	}
+
+	return
+}
+
+// xmppNextElement skips the stream tag and decodes the next element into
+// v, other elements and stream errors fail
+func xmppNextElement(d *xml.Decoder, space string, local string, v interface{}) error {
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch {
+			case t.Name.Space == xmppStreamsNS && t.Name.Local == "stream":
+				continue
+			case t.Name.Space == xmppStreamsNS && t.Name.Local == "error":
+				var e xmppStreamError
+				d.DecodeElement(&e, &t)
+				return fmt.Errorf("xmpp stream error: %s", e.Condition.XMLName.Local)
+			case t.Name.Space == xmppTLSNS && t.Name.Local == "failure":
+				return errors.New("xmpp starttls failure")
+			case t.Name.Space != space || t.Name.Local != local:
+				return fmt.Errorf("unexpected xmpp element: %s", t.Name.Local)
+			}
+
+			if v == nil {
+				return d.Skip()
+			}
+			return d.DecodeElement(v, &t)
+		case xml.EndElement:
+			return errors.New("xmpp stream closed")
+		}
+	}
+}
+
+// xmppTLSRequired reports if the XMPP server marks STARTTLS as required
+func xmppTLSRequired(host string, port string, opts Options) (bool, error) {
+	conn, err := opts.dial(host, port, 3*time.Second)
+	if err != nil {
+		logger.Debugf("event_id=tcp_dial_failed server=%s:%s msg\"%v\"", host, port, err)
+		return false, err
+	}
+	defer conn.Close()
+
+	return xmppStartTLS(conn, opts.StartTLS.ServiceFor(port), opts.StartTLS.xmppDomain(host))
+}
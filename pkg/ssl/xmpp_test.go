@@ -0,0 +1,140 @@
+package ssl
+
+import (
+	"crypto/tls"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+)
+
+const testXMPPDomain = "xmpp.example.com"
+
+// fakeXMPPServer answers a stream header to domain with features and the
+// STARTTLS request with starttlsResp
+type fakeXMPPServer struct {
+	domain       string
+	ns           string
+	features     string
+	starttlsResp string
+}
+
+func (s fakeXMPPServer) handle(conn net.Conn, cfg *tls.Config) {
+	d := xml.NewDecoder(byteReader{r: conn})
+
+	var to, ns string
+	for to == "" {
+		tok, err := d.Token()
+		if err != nil {
+			return
+		}
+		if t, ok := tok.(xml.StartElement); ok && t.Name.Local == "stream" {
+			for _, a := range t.Attr {
+				switch {
+				case a.Name.Local == "to":
+					to = a.Value
+				case a.Name.Space == "" && a.Name.Local == "xmlns":
+					ns = a.Value
+				}
+			}
+		}
+	}
+
+	header := fmt.Sprintf("<?xml version='1.0'?><stream:stream xmlns='%s' xmlns:stream='%s' from='%s' id='1' version='1.0'>", ns, xmppStreamsNS, s.domain)
+	if to != s.domain || ns != s.ns {
+		conn.Write([]byte(header + "<stream:error><host-unknown xmlns='urn:ietf:params:xml:ns:xmpp-streams'/></stream:error></stream:stream>"))
+		return
+	}
+	conn.Write([]byte(header + "<stream:features>" + s.features + "</stream:features>"))
+
+	tok, err := d.Token()
+	if t, ok := tok.(xml.StartElement); err != nil || !ok || t.Name.Local != "starttls" {
+		return
+	}
+	conn.Write([]byte(s.starttlsResp))
+
+	if s.starttlsResp == "<proceed xmlns='urn:ietf:params:xml:ns:xmpp-tls'/>" {
+		tls.Server(conn, cfg).Handshake()
+	}
+}
+
+const (
+	testXMPPProceed  = "<proceed xmlns='urn:ietf:params:xml:ns:xmpp-tls'/>"
+	testXMPPStartTLS = "<starttls xmlns='urn:ietf:params:xml:ns:xmpp-tls'/>"
+	testXMPPRequired = "<starttls xmlns='urn:ietf:params:xml:ns:xmpp-tls'><required/></starttls>"
+	testXMPPSASL     = "<mechanisms xmlns='urn:ietf:params:xml:ns:xmpp-sasl'><mechanism>PLAIN</mechanism></mechanisms>"
+)
+
+func TestXMPPStartTLS(t *testing.T) {
+	var tests = []struct {
+		name     string
+		service  string
+		domain   string
+		server   fakeXMPPServer
+		required bool
+		err      error
+	}{
+		{"c2s required", "xmpp", testXMPPDomain, fakeXMPPServer{testXMPPDomain, xmppClientNS, testXMPPRequired + testXMPPSASL, testXMPPProceed}, true, nil},
+		{"s2s optional", "xmpp-server", testXMPPDomain, fakeXMPPServer{testXMPPDomain, xmppServerNS, testXMPPStartTLS, testXMPPProceed}, false, nil},
+		{"not offered", "xmpp", testXMPPDomain, fakeXMPPServer{testXMPPDomain, xmppClientNS, testXMPPSASL, testXMPPProceed}, false, errStartTLSNotSupported},
+		{"failure", "xmpp", testXMPPDomain, fakeXMPPServer{testXMPPDomain, xmppClientNS, testXMPPStartTLS, "<failure xmlns='urn:ietf:params:xml:ns:xmpp-tls'/></stream:stream>"}, false, errors.New("xmpp starttls failure")},
+		{"unknown host", "xmpp", "other.example.com", fakeXMPPServer{testXMPPDomain, xmppClientNS, testXMPPStartTLS, testXMPPProceed}, false, errors.New("xmpp stream error: host-unknown")},
+	}
+
+	for _, test := range tests {
+		host, port := startServiceServer(t, test.server.handle)
+
+		conn, err := net.Dial("tcp", net.JoinHostPort(host, port))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		required, err := xmppStartTLS(conn, test.service, test.domain)
+		if fmt.Sprint(err) != fmt.Sprint(test.err) || required != test.required {
+			t.Errorf("%s: got required: %v err: %v, want required: %v err: %v", test.name, required, err, test.required, test.err)
+		}
+
+		if err == nil {
+			client := tls.Client(conn, &tls.Config{InsecureSkipVerify: true})
+			if err := client.Handshake(); err != nil {
+				t.Errorf("%s: tls handshake failed after proceed: %v", test.name, err)
+			}
+		}
+		conn.Close()
+	}
+}
+
+func TestXMPPDomain(t *testing.T) {
+	host, port := startServiceServer(t, fakeXMPPServer{testXMPPDomain, xmppClientNS, testXMPPRequired, testXMPPProceed}.handle)
+
+	opts := serviceOptions("xmpp")
+	if d := opts.StartTLS.xmppDomain(host); d != host {
+		t.Errorf("domain should default to host, got: %s", d)
+	}
+	if _, err := xmppTLSRequired(host, port, opts); err == nil {
+		t.Errorf("stream to host should fail")
+	}
+
+	opts.StartTLS.XMPPDomain = testXMPPDomain
+	if required, err := xmppTLSRequired(host, port, opts); err != nil || !required {
+		t.Errorf("tls should be required, got: %v err: %v", required, err)
+	}
+}
+
+func TestConnStateXMPP(t *testing.T) {
+	host, port := startServiceServer(t, fakeXMPPServer{testXMPPDomain, xmppClientNS, testXMPPRequired, testXMPPProceed}.handle)
+
+	opts := serviceOptions("xmpp")
+	opts.StartTLS.XMPPDomain = testXMPPDomain
+
+	state, _ := ConnStateWithOptions(host, port, opts)
+	if len(state.PeerCertificates) == 0 {
+		t.Errorf("no certificates received over xmpp")
+	}
+
+	f := CheckFeatures(host, port, nil, opts)
+	if f.XMPPTLSRequired == nil || !*f.XMPPTLSRequired {
+		t.Errorf("tls should be reported as required, got: %v", f.XMPPTLSRequired)
+	}
+}
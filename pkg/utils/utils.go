@@ -77,9 +77,15 @@ func BytetoInt(s []byte) int {
 	return int(binary.BigEndian.Uint64(b[:]))
 }
 
-// GetHTTPHeader used to get server header/name
-func GetHTTPHeader(host string, port string, name string) (string, error) {
+// acceptEncoding is sent to find out if the server compresses responses
+const acceptEncoding = "gzip, deflate, br"
+
+// GetHTTPHeader used to get server header/name. The request is sent with
+// Accept-Encoding and the Content-Encoding of the response is returned
+// as well, which is empty when the response is not compressed
+func GetHTTPHeader(host string, port string, name string) (string, string, error) {
 	var header string
+	var encoding string
 
 	server := host
 
@@ -93,8 +99,9 @@ func GetHTTPHeader(host string, port string, name string) (string, error) {
 	}
 
 	httpTransport := &http.Transport{
-		Proxy:           http.ProxyFromEnvironment,
-		TLSClientConfig: tlsCfg,
+		Proxy:              http.ProxyFromEnvironment,
+		TLSClientConfig:    tlsCfg,
+		DisableCompression: true,
 	}
 	httpClient := &http.Client{
 		Transport: httpTransport,
@@ -104,16 +111,29 @@ func GetHTTPHeader(host string, port string, name string) (string, error) {
 		},
 	}
 
-	resp, err := httpClient.Get("https://" + server)
+	req, err := http.NewRequest(http.MethodGet, "https://"+server, nil)
+	if err != nil {
+		return header, encoding, err
+	}
+	req.Header.Set("Accept-Encoding", acceptEncoding)
+
+	resp, err := httpClient.Do(req)
 	if err != nil {
 		logger.Warnf("event_id=http_client_failed msg=\"%v\"", err)
-		return header, err
+		return header, encoding, err
 	}
+	defer resp.Body.Close()
 
 	header = resp.Header.Get(name)
 	logger.Debugf("event_id=retrieved_header name=%s value=%s", name, header)
 
-	return header, err
+	encoding = resp.Header.Get("Content-Encoding")
+	if encoding == "identity" {
+		encoding = ""
+	}
+	logger.Debugf("event_id=retrieved_content_encoding value=%s", encoding)
+
+	return header, encoding, err
 }
 
 // GetService returns name of service based on port
@@ -135,8 +155,20 @@ func GetService(port string) (proto string) {
 		proto = "imap"
 	case "993":
 		proto = "imapSSL"
+	case "389":
+		proto = "ldap"
+	case "636":
+		proto = "ldapSSL"
+	case "3306":
+		proto = "mysql"
 	case "3389":
 		proto = "rdp"
+	case "5432":
+		proto = "postgres"
+	case "5222":
+		proto = "xmpp"
+	case "5269":
+		proto = "xmpp-server"
 	default:
 		proto = "https"
 	}
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"compress/gzip"
 	"encoding/hex"
 	"net/http"
 	"net/http/httptest"
@@ -95,11 +96,33 @@ func TestGetHTTPHeader(t *testing.T) {
 	s := strings.Split(strings.Replace(server.URL, "https://", "", -1), ":")
 	host := s[0]
 	port := s[1]
-	h, _ := GetHTTPHeader(host, port, "Server")
+	h, e, _ := GetHTTPHeader(host, port, "Server")
 
 	if h != "Apache" {
 		t.Errorf("failed to get server header, got: %s, want: %s.", h, "Apache")
 	}
+	if e != "" {
+		t.Errorf("response should not be compressed, got: %s", e)
+	}
+}
+
+func TestGetHTTPHeaderCompressed(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if strings.Contains(req.Header.Get("Accept-Encoding"), "gzip") {
+			rw.Header().Set("Content-Encoding", "gzip")
+			gz := gzip.NewWriter(rw)
+			gz.Write([]byte("Hello"))
+			gz.Close()
+		}
+	}))
+	defer server.Close()
+
+	s := strings.Split(strings.Replace(server.URL, "https://", "", -1), ":")
+	_, e, err := GetHTTPHeader(s[0], s[1], "Server")
+
+	if err != nil || e != "gzip" {
+		t.Errorf("failed to get content encoding, got: %s, %v, want: %s.", e, err, "gzip")
+	}
 }
 
 func TestGetService(t *testing.T) {
@@ -113,7 +136,13 @@ func TestGetService(t *testing.T) {
 		"143":  "imap",
 		"993":  "imapSSL",
 		"443":  "https",
+		"389":  "ldap",
+		"636":  "ldapSSL",
+		"3306": "mysql",
 		"3389": "rdp",
+		"5432": "postgres",
+		"5222": "xmpp",
+		"5269": "xmpp-server",
 	}
 
 	for k, v := range l {
@@ -158,8 +158,12 @@ def scan_test(host, data):
     key_type = cert['keyType']
     server = conn['serverHeader']
     config = conn['supportedConfig']
-    hbleed = vuln['heartbleed']
-    ccs = vuln['ccsinjection']
+    findings = {f['id']: f for f in vuln['findings']}
+    hbleed = {
+        "vulnerable": findings['heartbleed']['status'],
+        "extension": "heartbeat extension enabled" in (findings['heartbleed'].get('evidence') or [])
+    }
+    ccs = {"vulnerable": findings['ccsInjection']['status']}
 
     if key_type != data['exp_key_type']:
         print("Host: {} wrong key type, got {}, wanted {}".format(host, key_type, data['exp_key_type']))